@@ -2,6 +2,7 @@ package upngo
 
 import (
 	"fmt"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -77,3 +78,124 @@ type AccountsResponse struct {
 type AccountResponse struct {
 	Data AccountResource `json:"data"`
 }
+
+// AccountsClient is a Client's accounts endpoints, e.g.
+// `client.Accounts.Get(id)`. Calling it lists accounts, like List, so code
+// from before the endpoints were grouped, `client.Accounts()`, still works.
+//
+// Create one with NewAccountsClient, otherwise its methods return
+// ErrNoImplementation.
+type AccountsClient func(options ...AccountsOption) (AccountsResponse, error)
+
+// NewAccountsClient creates an AccountsClient that uses `api`, e.g. to replace
+// a Client's Accounts with a mock.
+func NewAccountsClient(api AccountsAPI) AccountsClient {
+	return func(options ...AccountsOption) (AccountsResponse, error) {
+		if len(options) == 1 && provide(options[0], api) {
+			return AccountsResponse{}, nil
+		}
+		return api.List(options...)
+	}
+}
+
+// api is the implementation the sub-client wraps. Only sub-clients created
+// with NewAccountsClient have one.
+func (c AccountsClient) api() (AccountsAPI, error) {
+	if c == nil {
+		return nil, ErrNoImplementation
+	}
+	api, ok := unwrap(func(request implementation) { c(request) }).(AccountsAPI)
+	if !ok {
+		return nil, ErrNoImplementation
+	}
+	return api, nil
+}
+
+// List lists all the accounts associated with the authenticated account.
+func (c AccountsClient) List(options ...AccountsOption) (AccountsResponse, error) {
+	if c == nil {
+		return AccountsResponse{}, ErrNoImplementation
+	}
+	return c(options...)
+}
+
+// Next retrieves the page of accounts after the given response. If there are
+// no more pages then ErrNoNextPage is returned.
+func (c AccountsClient) Next(accounts AccountsResponse) (AccountsResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return AccountsResponse{}, err
+	}
+	return api.Next(accounts)
+}
+
+// Get retrieves an account by its ID.
+func (c AccountsClient) Get(id string) (AccountResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return AccountResponse{}, err
+	}
+	return api.Get(id)
+}
+
+// Transactions lists the transactions for the account with the given ID.
+func (c AccountsClient) Transactions(accountID string, options ...TransactionsOption) (TransactionsResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return TransactionsResponse{}, err
+	}
+	return api.Transactions(accountID, options...)
+}
+
+// AccountsService talks to the accounts endpoints. It's what a Client's
+// Accounts uses unless it's been replaced.
+type AccountsService struct {
+	client *Client
+}
+
+// List lists all the accounts associated with the authenticated account.
+func (s *AccountsService) List(options ...AccountsOption) (AccountsResponse, error) {
+	return s.list(s.client.buildURL("accounts"), options...)
+}
+
+// Next retrieves the page of accounts after the given response. If there are
+// no more pages then ErrNoNextPage is returned.
+func (s *AccountsService) Next(accounts AccountsResponse) (AccountsResponse, error) {
+	if accounts.Links.Next == "" {
+		return AccountsResponse{}, ErrNoNextPage
+	}
+	return s.list(accounts.Links.Next)
+}
+
+func (s *AccountsService) list(url string, options ...AccountsOption) (AccountsResponse, error) {
+	query := make([]Option, len(options))
+	for i, option := range options {
+		query[i] = option
+	}
+
+	var accountsResponse AccountsResponse
+	err := s.client.do(request{
+		name:    "get accounts",
+		method:  http.MethodGet,
+		url:     url,
+		options: query,
+	}, &accountsResponse)
+	return accountsResponse, err
+}
+
+// Get retrieves an account by its ID.
+func (s *AccountsService) Get(id string) (AccountResponse, error) {
+	var accountResponse AccountResponse
+	err := s.client.do(request{
+		name:   "get account by ID",
+		method: http.MethodGet,
+		url:    s.client.buildURL("accounts", id),
+	}, &accountResponse)
+	return accountResponse, err
+}
+
+// Transactions lists the transactions for the account with the given ID. The
+// following pages can be retrieved with the Transactions service's Next.
+func (s *AccountsService) Transactions(accountID string, options ...TransactionsOption) (TransactionsResponse, error) {
+	return (&TransactionsService{client: s.client}).list(s.client.buildURL("accounts", accountID, "transactions"), options...)
+}
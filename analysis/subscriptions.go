@@ -0,0 +1,233 @@
+// Package analysis finds patterns in transaction history.
+package analysis
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nick96/upngo"
+)
+
+// Frequency is how often a recurring payment is charged.
+type Frequency string
+
+const (
+	FrequencyWeekly      Frequency = "weekly"
+	FrequencyFortnightly Frequency = "fortnightly"
+	FrequencyMonthly     Frequency = "monthly"
+	FrequencyYearly      Frequency = "yearly"
+)
+
+// frequency describes what intervals between charges count as a frequency.
+type frequency struct {
+	name Frequency
+	// days is the typical interval and tolerance is how far either side of
+	// it an interval can be and still count. Months vary in length and
+	// charges don't always land on the same day so there's some slack.
+	days      int
+	tolerance int
+	perYear   int64
+	next      func(time.Time) time.Time
+}
+
+var frequencies = []frequency{
+	{FrequencyWeekly, 7, 1, 52, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }},
+	{FrequencyFortnightly, 14, 2, 26, func(t time.Time) time.Time { return t.AddDate(0, 0, 14) }},
+	{FrequencyMonthly, 30, 4, 12, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{FrequencyYearly, 365, 10, 1, func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+}
+
+// Subscription is a recurring payment to the same merchant. Amounts are
+// positive and in the currency's base units.
+type Subscription struct {
+	Merchant  string
+	Frequency Frequency
+	// Charges are the transactions for the subscription, oldest first.
+	Charges    []upngo.TransactionResource
+	Currency   string
+	Amount     int64
+	LastCharge time.Time
+	NextCharge time.Time
+	// AnnualCost is what the subscription costs per year at the current
+	// amount.
+	AnnualCost int64
+	// PreviousAmount is the amount of the charge before the last one. If it
+	// differs from Amount then the price has changed.
+	PreviousAmount int64
+
+	tolerance int
+}
+
+// PriceChanged reports whether the last charge was different to the one
+// before it.
+func (s Subscription) PriceChanged() bool {
+	return s.Amount != s.PreviousAmount
+}
+
+// Lapsed reports whether the next charge was expected a while before `now`
+// but hasn't happened, which probably means the subscription was cancelled.
+func (s Subscription) Lapsed(now time.Time) bool {
+	return now.After(s.NextCharge.AddDate(0, 0, s.tolerance))
+}
+
+// Options tune how subscriptions are detected.
+type Options struct {
+	// MinCharges is the fewest charges a merchant needs before it is
+	// considered recurring. Yearly subscriptions only ever need two because
+	// waiting for a third takes a long time. Defaults to 3.
+	MinCharges int
+	// AmountTolerance is how far, as a percentage, each charge can be from
+	// the typical charge for the amount to still be considered stable.
+	// Defaults to 20.
+	AmountTolerance int64
+}
+
+// NormaliseMerchant turns a transaction's description into a key that is the
+// same for every charge from a merchant, e.g. "NETFLIX.COM 0412" and
+// "Netflix.com" are both "netflix com". The description is used in preference
+// to the raw text because UpBank has already cleaned it up.
+func NormaliseMerchant(transaction upngo.TransactionResource) string {
+	text := transaction.Attributes.Description
+	if text == "" {
+		text = transaction.Attributes.RawText
+	}
+
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		// Numbers tend to be references that change with every charge.
+		if strings.IndexFunc(word, unicode.IsLetter) >= 0 {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// DetectSubscriptions finds the merchants in the transactions that are charged
+// at regular intervals with stable amounts. The subscriptions are sorted by
+// annual cost, most expensive first.
+func DetectSubscriptions(transactions []upngo.TransactionResource, options Options) []Subscription {
+	if options.MinCharges == 0 {
+		options.MinCharges = 3
+	}
+	if options.AmountTolerance == 0 {
+		options.AmountTolerance = 20
+	}
+
+	byMerchant := make(map[string][]upngo.TransactionResource)
+	for _, transaction := range transactions {
+		// Only money going out to someone else can be a subscription.
+		if transaction.Attributes.Amount.ValueInBaseUnits >= 0 ||
+			transaction.Relationships.TransferAccount.Data != nil {
+			continue
+		}
+		merchant := NormaliseMerchant(transaction)
+		if merchant == "" {
+			continue
+		}
+		byMerchant[merchant] = append(byMerchant[merchant], transaction)
+	}
+
+	var subscriptions []Subscription
+	for _, charges := range byMerchant {
+		sort.SliceStable(charges, func(i, j int) bool {
+			return charges[i].Attributes.CreatedAt.Before(charges[j].Attributes.CreatedAt)
+		})
+		if subscription, ok := detect(charges, options); ok {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if subscriptions[i].AnnualCost == subscriptions[j].AnnualCost {
+			return subscriptions[i].Merchant < subscriptions[j].Merchant
+		}
+		return subscriptions[i].AnnualCost > subscriptions[j].AnnualCost
+	})
+	return subscriptions
+}
+
+// detect works out whether the charges, which are all from the same merchant
+// and sorted oldest first, are a subscription.
+func detect(charges []upngo.TransactionResource, options Options) (Subscription, bool) {
+	if len(charges) < 2 {
+		return Subscription{}, false
+	}
+
+	var intervals []int
+	for i := 1; i < len(charges); i++ {
+		interval := charges[i].Attributes.CreatedAt.Sub(charges[i-1].Attributes.CreatedAt)
+		intervals = append(intervals, int((interval+12*time.Hour).Hours()/24))
+	}
+
+	freq, ok := classify(intervals)
+	if !ok {
+		return Subscription{}, false
+	}
+	if freq.name != FrequencyYearly && len(charges) < options.MinCharges {
+		return Subscription{}, false
+	}
+
+	amounts := make([]int64, len(charges))
+	for i, charge := range charges {
+		amounts[i] = -charge.Attributes.Amount.ValueInBaseUnits
+	}
+	if !stable(amounts, options.AmountTolerance) {
+		return Subscription{}, false
+	}
+
+	last := charges[len(charges)-1]
+	amount := amounts[len(amounts)-1]
+	return Subscription{
+		Merchant:       last.Attributes.Description,
+		Frequency:      freq.name,
+		Charges:        charges,
+		Currency:       last.Attributes.Amount.CurrencyCode,
+		Amount:         amount,
+		LastCharge:     last.Attributes.CreatedAt,
+		NextCharge:     freq.next(last.Attributes.CreatedAt),
+		AnnualCost:     amount * freq.perYear,
+		PreviousAmount: amounts[len(amounts)-2],
+		tolerance:      freq.tolerance,
+	}, true
+}
+
+// classify finds the frequency that the intervals, in days, fit. At least
+// three quarters of the intervals have to fit so that the odd missed or extra
+// charge doesn't hide a subscription.
+func classify(intervals []int) (frequency, bool) {
+	for _, freq := range frequencies {
+		fits := 0
+		for _, interval := range intervals {
+			if interval >= freq.days-freq.tolerance && interval <= freq.days+freq.tolerance {
+				fits++
+			}
+		}
+		if fits*4 >= len(intervals)*3 {
+			return freq, true
+		}
+	}
+	return frequency{}, false
+}
+
+// stable reports whether every amount is within `tolerance` percent of the
+// median amount.
+func stable(amounts []int64, tolerance int64) bool {
+	sorted := append([]int64{}, amounts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	median := sorted[len(sorted)/2]
+
+	for _, amount := range amounts {
+		difference := amount - median
+		if difference < 0 {
+			difference = -difference
+		}
+		if difference*100 > median*tolerance {
+			return false
+		}
+	}
+	return true
+}
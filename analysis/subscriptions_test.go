@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+func newCharge(description string, baseUnits int64, createdAt time.Time) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.ID = description + createdAt.String()
+	transaction.Attributes.Description = description
+	transaction.Attributes.Amount = upngo.MoneyObject{CurrencyCode: "AUD", ValueInBaseUnits: baseUnits}
+	transaction.Attributes.CreatedAt = createdAt
+	return transaction
+}
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2020, month, day, 9, 0, 0, 0, time.UTC)
+}
+
+func TestNormaliseMerchant(t *testing.T) {
+	require.Equal(t, "netflix com", NormaliseMerchant(newCharge("NETFLIX.COM 0412", -1, date(1, 1))))
+
+	var transaction upngo.TransactionResource
+	transaction.Attributes.RawText = "Spotify P0F2A"
+	require.Equal(t, "spotify p0f2a", NormaliseMerchant(transaction))
+}
+
+func TestDetectSubscriptions(t *testing.T) {
+	transactions := []upngo.TransactionResource{
+		// Monthly, with a price rise on the last charge.
+		newCharge("Netflix", -1399, date(5, 3)),
+		newCharge("Netflix", -1399, date(6, 3)),
+		newCharge("Netflix", -1399, date(7, 3)),
+		newCharge("Netflix", -1599, date(8, 3)),
+		// Fortnightly.
+		newCharge("Gym", -2000, date(7, 1)),
+		newCharge("Gym", -2000, date(7, 15)),
+		newCharge("Gym", -2000, date(7, 29)),
+		// Irregular.
+		newCharge("Coffee", -450, date(7, 1)),
+		newCharge("Coffee", -450, date(7, 2)),
+		newCharge("Coffee", -450, date(7, 20)),
+		// Regular but the amount is all over the place.
+		newCharge("Woolies", -5000, date(7, 1)),
+		newCharge("Woolies", -15000, date(7, 8)),
+		newCharge("Woolies", -2000, date(7, 15)),
+		// Money coming in isn't a subscription.
+		newCharge("Pay", 100000, date(7, 1)),
+		newCharge("Pay", 100000, date(7, 15)),
+		newCharge("Pay", 100000, date(7, 29)),
+		// Yearly only needs two charges.
+		newCharge("Domain", -2000, time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)),
+		newCharge("Domain", -2000, date(6, 2)),
+	}
+
+	subscriptions := DetectSubscriptions(transactions, Options{})
+	require.Len(t, subscriptions, 3)
+
+	gym, netflix, domain := subscriptions[0], subscriptions[1], subscriptions[2]
+	require.Equal(t, "Gym", gym.Merchant)
+	require.Equal(t, FrequencyFortnightly, gym.Frequency)
+	require.Equal(t, int64(52000), gym.AnnualCost)
+	require.Equal(t, date(8, 12), gym.NextCharge)
+	require.False(t, gym.PriceChanged())
+
+	require.Equal(t, "Netflix", netflix.Merchant)
+	require.Equal(t, FrequencyMonthly, netflix.Frequency)
+	require.Equal(t, int64(1599), netflix.Amount)
+	require.Equal(t, int64(1399), netflix.PreviousAmount)
+	require.True(t, netflix.PriceChanged())
+	require.Equal(t, int64(1599*12), netflix.AnnualCost)
+	require.Equal(t, date(9, 3), netflix.NextCharge)
+	require.Len(t, netflix.Charges, 4)
+
+	require.Equal(t, FrequencyYearly, domain.Frequency)
+	require.False(t, domain.Lapsed(date(9, 1)))
+	require.True(t, domain.Lapsed(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,98 @@
+package upngo
+
+// AccountsAPI is the accounts endpoints. It's implemented by AccountsService
+// and, for tests, by the mock package.
+type AccountsAPI interface {
+	List(options ...AccountsOption) (AccountsResponse, error)
+	Next(accounts AccountsResponse) (AccountsResponse, error)
+	Get(id string) (AccountResponse, error)
+	Transactions(accountID string, options ...TransactionsOption) (TransactionsResponse, error)
+}
+
+// TransactionsAPI is the transactions endpoints. It's implemented by
+// TransactionsService and, for tests, by the mock package.
+type TransactionsAPI interface {
+	List(options ...TransactionsOption) (TransactionsResponse, error)
+	Next(transactions TransactionsResponse) (TransactionsResponse, error)
+	Get(id string) (TransactionResponse, error)
+	Categorize(transactionID string, categoryID string) error
+	Tag(transactionID string, tags ...string) error
+	Untag(transactionID string, tags ...string) error
+}
+
+// CategoriesAPI is the categories endpoints. It's implemented by
+// CategoriesService and, for tests, by the mock package.
+type CategoriesAPI interface {
+	List(options ...CategoriesOption) (CategoriesResponse, error)
+	Get(id string) (CategoryResponse, error)
+}
+
+// TagsAPI is the tags endpoint. It's implemented by TagsService and, for
+// tests, by the mock package.
+type TagsAPI interface {
+	List(options ...TagsOption) (TagsResponse, error)
+	Next(tags TagsResponse) (TagsResponse, error)
+}
+
+// WebhooksAPI is the webhooks endpoints. It's implemented by WebhooksService
+// and, for tests, by the mock package.
+type WebhooksAPI interface {
+	List(options ...WebhooksOption) (WebhooksResponse, error)
+	Next(webhooks WebhooksResponse) (WebhooksResponse, error)
+	Get(id string) (WebhookResponse, error)
+	Create(webhookURL string, options ...RegisterWebhookOption) (WebhookResponse, error)
+	Delete(id string) error
+	Ping(id string) (WebhookPingResponse, error)
+	Logs(id string, options ...WebhookLogsOption) (WebhookLogsResponse, error)
+	NextLogs(logs WebhookLogsResponse) (WebhookLogsResponse, error)
+}
+
+var (
+	_ AccountsAPI     = (*AccountsService)(nil)
+	_ TransactionsAPI = (*TransactionsService)(nil)
+	_ CategoriesAPI   = (*CategoriesService)(nil)
+	_ TagsAPI         = (*TagsService)(nil)
+	_ WebhooksAPI     = (*WebhooksService)(nil)
+
+	_ AccountsAPI     = AccountsClient(nil)
+	_ TransactionsAPI = TransactionsClient(nil)
+	_ CategoriesAPI   = CategoriesClient(nil)
+	_ TagsAPI         = TagsClient(nil)
+	_ WebhooksAPI     = WebhooksClient(nil)
+)
+
+// implementation asks a sub-client, like AccountsClient, for the
+// implementation it wraps. Sub-clients are functions so that they can still be
+// called like the methods they replaced, e.g. `client.Accounts()`, which means
+// the only way to get at what's inside one is to call it. This is passed as
+// its only option to do that: the sub-client stores its implementation in
+// `api` instead of making a request.
+type implementation struct {
+	api *interface{}
+}
+
+func (implementation) Name() string  { return "" }
+func (implementation) Value() string { return "" }
+
+func (implementation) accountsOption()     {}
+func (implementation) transactionsOption() {}
+func (implementation) categoriesOption()   {}
+func (implementation) tagsOption()         {}
+func (implementation) webhooksOption()     {}
+
+// provide gives `api` to `option` if it's asking for the implementation, and
+// reports whether it was.
+func provide(option Option, api interface{}) bool {
+	request, ok := option.(implementation)
+	if ok {
+		*request.api = api
+	}
+	return ok
+}
+
+// unwrap calls a sub-client with `call` to get the implementation it wraps.
+func unwrap(call func(implementation)) interface{} {
+	var api interface{}
+	call(implementation{api: &api})
+	return api
+}
@@ -0,0 +1,71 @@
+package upngo
+
+import (
+	"context"
+	"sync"
+)
+
+// TransactionResult is the outcome of fetching one transaction with
+// TransactionsByID.
+type TransactionResult struct {
+	ID          string
+	Transaction TransactionResource
+	Err         error
+}
+
+// TransactionsByID fetches the transactions with the given IDs using up to
+// `concurrency` requests at once. There's a result for every ID in the same
+// order as `ids`, each with its own error, so one missing transaction doesn't
+// stop the rest being fetched. IDs that appear more than once are only fetched
+// once. The requests are made with `ctx` and go through the client's rate
+// limiter; once `ctx` is done the transactions that haven't been fetched yet
+// fail with its error.
+func (c *Client) TransactionsByID(ctx context.Context, ids []string, concurrency int) []TransactionResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	client := c.WithContext(ctx)
+
+	// Work out the unique IDs, remembering where each one goes in the
+	// results.
+	positions := make(map[string][]int)
+	var unique []string
+	for i, id := range ids {
+		if _, ok := positions[id]; !ok {
+			unique = append(unique, id)
+		}
+		positions[id] = append(positions[id], i)
+	}
+
+	results := make([]TransactionResult, len(ids))
+	work := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(unique); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range work {
+				result := TransactionResult{ID: id}
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+				} else {
+					response, err := client.Transactions.Get(id)
+					result.Transaction, result.Err = response.Data, err
+				}
+				// Each ID's positions are only written by the worker
+				// that fetched it so there's no need to lock.
+				for _, position := range positions[id] {
+					results[position] = result
+				}
+			}
+		}()
+	}
+
+	for _, id := range unique {
+		work <- id
+	}
+	close(work)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,209 @@
+// Package budget tracks spending against budgets for a category or tag.
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+// Period is how often a budget resets.
+type Period string
+
+const (
+	PeriodWeekly  Period = "weekly"
+	PeriodMonthly Period = "monthly"
+)
+
+// DefaultThresholds are the percentages of a budget that trigger an alert when
+// a budget doesn't specify its own.
+var DefaultThresholds = []int{80, 100}
+
+// Budget is a limit on how much can be spent in a category or on transactions
+// with a tag each period. Exactly one of Category or Tag should be set.
+type Budget struct {
+	Category string `mapstructure:"category" yaml:"category,omitempty"`
+	Tag      string `mapstructure:"tag" yaml:"tag,omitempty"`
+	// Amount is the limit as a decimal, e.g. "500.00".
+	Amount   string `mapstructure:"amount" yaml:"amount"`
+	Currency string `mapstructure:"currency" yaml:"currency,omitempty"`
+	Period   Period `mapstructure:"period" yaml:"period,omitempty"`
+	// Thresholds are the percentages of Amount that trigger an alert once
+	// spending reaches them. If empty, DefaultThresholds are used.
+	Thresholds []int `mapstructure:"thresholds" yaml:"thresholds,omitempty"`
+}
+
+// Validate checks the budget makes sense.
+func (b Budget) Validate() error {
+	if (b.Category == "") == (b.Tag == "") {
+		return errors.New("budget needs exactly one of a category or tag")
+	}
+	switch b.period() {
+	case PeriodWeekly, PeriodMonthly:
+	default:
+		return fmt.Errorf("unknown period %q", b.Period)
+	}
+	amount, err := upngo.ParseBaseUnits(b.Amount, b.currency())
+	if err != nil {
+		return fmt.Errorf("invalid amount: %w", err)
+	}
+	// A limit of zero would set off every threshold straight away.
+	if amount <= 0 {
+		return fmt.Errorf("amount %s isn't positive", b.Amount)
+	}
+	for _, threshold := range b.Thresholds {
+		if threshold <= 0 {
+			return fmt.Errorf("threshold %d%% isn't positive", threshold)
+		}
+	}
+	return nil
+}
+
+func (b Budget) period() Period {
+	if b.Period == "" {
+		return PeriodMonthly
+	}
+	return b.Period
+}
+
+func (b Budget) currency() string {
+	if b.Currency == "" {
+		return "AUD"
+	}
+	return b.Currency
+}
+
+func (b Budget) thresholds() []int {
+	if len(b.Thresholds) == 0 {
+		return DefaultThresholds
+	}
+	thresholds := append([]int{}, b.Thresholds...)
+	sort.Ints(thresholds)
+	return thresholds
+}
+
+// Bounds gets the start and end of the period that `now` is in. Weeks start on
+// Monday.
+func (b Budget) Bounds(now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if b.period() == PeriodWeekly {
+		start := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7)
+	}
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+// matches reports whether the transaction counts towards the budget.
+func (b Budget) matches(transaction upngo.TransactionResource) bool {
+	relationships := transaction.Relationships
+	if relationships.TransferAccount.Data != nil {
+		return false
+	}
+	if b.Category != "" {
+		for _, category := range []upngo.OptionalRelationshipObject{relationships.Category, relationships.ParentCategory} {
+			if category.Data != nil && category.Data.ID == b.Category {
+				return true
+			}
+		}
+		return false
+	}
+	for _, tag := range relationships.Tags.Data {
+		if tag.ID == b.Tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Status is where spending is at against a budget for the current period.
+// Amounts are in the currency's base units.
+type Status struct {
+	Name   string
+	Budget Budget
+	Start  time.Time
+	End    time.Time
+	Limit  int64
+	// Spent is the spending so far this period, less any refunds.
+	Spent int64
+	// Projected is what will have been spent by the end of the period if
+	// spending continues at the same rate.
+	Projected int64
+	// Crossed are the thresholds that spending has reached.
+	Crossed []int
+}
+
+// Remaining is how much is left to spend this period. It's negative if the
+// budget has been exceeded.
+func (s Status) Remaining() int64 {
+	return s.Limit - s.Spent
+}
+
+// Percent is the percentage of the budget that has been spent.
+func (s Status) Percent() int64 {
+	if s.Limit == 0 {
+		return 0
+	}
+	return s.Spent * 100 / s.Limit
+}
+
+// Alerting reports whether any thresholds have been crossed.
+func (s Status) Alerting() bool {
+	return len(s.Crossed) > 0
+}
+
+// Evaluate works out the status of the budget at `now` from the transactions.
+// Transactions outside the current period are ignored so it's fine to give it
+// more than needed.
+func Evaluate(name string, budget Budget, transactions []upngo.TransactionResource, now time.Time) (Status, error) {
+	if err := budget.Validate(); err != nil {
+		return Status{}, fmt.Errorf("budget %s is invalid: %w", name, err)
+	}
+
+	limit, _ := upngo.ParseBaseUnits(budget.Amount, budget.currency())
+	start, end := budget.Bounds(now)
+	status := Status{
+		Name:   name,
+		Budget: budget,
+		Start:  start,
+		End:    end,
+		Limit:  limit,
+	}
+
+	for _, transaction := range transactions {
+		createdAt := transaction.Attributes.CreatedAt
+		if createdAt.Before(start) || !createdAt.Before(end) || !budget.matches(transaction) {
+			continue
+		}
+		amount := transaction.Attributes.Amount
+		if amount.CurrencyCode != budget.currency() {
+			return Status{}, fmt.Errorf(
+				"transaction %s is in %s but budget %s is in %s",
+				transaction.ID,
+				amount.CurrencyCode,
+				name,
+				budget.currency(),
+			)
+		}
+		// Spending is negative so subtracting it adds to what's been
+		// spent, while refunds take away from it.
+		status.Spent -= amount.ValueInBaseUnits
+	}
+
+	// Seconds are plenty precise and keep the multiplication well away from
+	// overflowing.
+	elapsed := int64(now.Sub(start) / time.Second)
+	if elapsed > 0 {
+		status.Projected = status.Spent * int64(end.Sub(start)/time.Second) / elapsed
+	}
+
+	for _, threshold := range budget.thresholds() {
+		if status.Spent*100 >= limit*int64(threshold) {
+			status.Crossed = append(status.Crossed, threshold)
+		}
+	}
+	return status, nil
+}
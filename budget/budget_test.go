@@ -0,0 +1,77 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+func newTransaction(category, tag string, baseUnits int64, createdAt time.Time) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.Attributes.Amount = upngo.MoneyObject{CurrencyCode: "AUD", ValueInBaseUnits: baseUnits}
+	transaction.Attributes.CreatedAt = createdAt
+	if category != "" {
+		transaction.Relationships.Category.Data = &upngo.DataObject{Type: "categories", ID: category}
+	}
+	if tag != "" {
+		transaction.Relationships.Tags.Data = []upngo.DataObject{{Type: "tags", ID: tag}}
+	}
+	return transaction
+}
+
+func TestEvaluate(t *testing.T) {
+	// Ten days into a thirty day month.
+	now := time.Date(2020, 9, 11, 0, 0, 0, 0, time.UTC)
+	transactions := []upngo.TransactionResource{
+		newTransaction("takeaway", "", -10000, time.Date(2020, 9, 2, 0, 0, 0, 0, time.UTC)),
+		newTransaction("takeaway", "", -8000, time.Date(2020, 9, 5, 0, 0, 0, 0, time.UTC)),
+		// Refunds reduce spending.
+		newTransaction("takeaway", "", 2000, time.Date(2020, 9, 6, 0, 0, 0, 0, time.UTC)),
+		// Last month doesn't count.
+		newTransaction("takeaway", "", -50000, time.Date(2020, 8, 30, 0, 0, 0, 0, time.UTC)),
+		newTransaction("groceries", "", -50000, time.Date(2020, 9, 3, 0, 0, 0, 0, time.UTC)),
+	}
+
+	status, err := Evaluate("takeaway", Budget{Category: "takeaway", Amount: "200"}, transactions, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(20000), status.Limit)
+	require.Equal(t, int64(16000), status.Spent)
+	require.Equal(t, int64(4000), status.Remaining())
+	require.Equal(t, int64(80), status.Percent())
+	require.Equal(t, int64(48000), status.Projected)
+	require.Equal(t, []int{80}, status.Crossed)
+	require.True(t, status.Alerting())
+	require.Equal(t, time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC), status.Start)
+	require.Equal(t, time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC), status.End)
+}
+
+func TestEvaluateWeeklyTag(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2020, 9, 9, 12, 0, 0, 0, time.UTC)
+	transactions := []upngo.TransactionResource{
+		newTransaction("", "Holiday", -5000, time.Date(2020, 9, 7, 0, 0, 0, 0, time.UTC)),
+		newTransaction("", "Holiday", -5000, time.Date(2020, 9, 6, 0, 0, 0, 0, time.UTC)),
+		newTransaction("", "Work", -5000, time.Date(2020, 9, 8, 0, 0, 0, 0, time.UTC)),
+	}
+
+	budget := Budget{Tag: "Holiday", Amount: "40.00", Period: PeriodWeekly, Thresholds: []int{150, 50, 100}}
+	status, err := Evaluate("holiday", budget, transactions, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(5000), status.Spent)
+	require.Equal(t, []int{50, 100}, status.Crossed)
+	require.Equal(t, time.Date(2020, 9, 7, 0, 0, 0, 0, time.UTC), status.Start)
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Budget{Category: "takeaway", Amount: "10"}.Validate())
+	require.Error(t, Budget{Amount: "10"}.Validate())
+	require.Error(t, Budget{Category: "takeaway", Tag: "Holiday", Amount: "10"}.Validate())
+	require.Error(t, Budget{Category: "takeaway", Amount: "ten"}.Validate())
+	require.Error(t, Budget{Category: "takeaway", Amount: ""}.Validate())
+	require.Error(t, Budget{Category: "takeaway", Amount: "0"}.Validate())
+	require.Error(t, Budget{Category: "takeaway", Amount: "-10"}.Validate())
+	require.Error(t, Budget{Category: "takeaway", Amount: "10", Period: "daily"}.Validate())
+	require.Error(t, Budget{Category: "takeaway", Amount: "10", Thresholds: []int{0}}.Validate())
+}
@@ -0,0 +1,242 @@
+// Package cache is an on-disk HTTP cache for responses from the UpBank API.
+// It's meant for reference data like accounts, categories and webhooks that
+// rarely changes, so each endpoint has its own time to live.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/nick96/upngo"
+)
+
+// DefaultTTLs are how long responses from each endpoint are cached for if no
+// others are given. Endpoints are templates as returned by upngo.Endpoint.
+// Accounts have a short TTL because their balances change with every
+// transaction. Transactions aren't cached at all.
+var DefaultTTLs = map[string]time.Duration{
+	"/accounts":        time.Minute,
+	"/accounts/{id}":   time.Minute,
+	"/categories":      24 * time.Hour,
+	"/categories/{id}": 24 * time.Hour,
+	"/tags":            time.Hour,
+	"/webhooks":        time.Hour,
+	"/webhooks/{id}":   time.Hour,
+}
+
+// entry is a cached response as it's stored on disk.
+type entry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"storedAt"`
+}
+
+// Cache stores responses in a directory. Responses for different tokens are
+// kept apart so one profile never sees another's data.
+type Cache struct {
+	dir  string
+	ttls map[string]time.Duration
+	now  func() time.Time
+}
+
+// DefaultDir is where the cache lives if no other directory is given. It
+// respects `XDG_CACHE_HOME`, falling back to `~/.cache`.
+func DefaultDir() (string, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find home directory: %w", err)
+		}
+		cacheHome = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(cacheHome, "upngo"), nil
+}
+
+// New creates a cache in `dir` with the given TTLs, or DefaultTTLs if `ttls` is
+// nil.
+func New(dir string, ttls map[string]time.Duration) *Cache {
+	if ttls == nil {
+		ttls = DefaultTTLs
+	}
+	return &Cache{dir: dir, ttls: ttls, now: time.Now}
+}
+
+// Middleware returns the cache as middleware for upngo.WithMiddleware.
+func (c *Cache) Middleware() func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &transport{cache: c, rt: rt}
+	}
+}
+
+// Option returns a client option that makes the client use the cache.
+func (c *Cache) Option() upngo.ClientOption {
+	return upngo.WithMiddleware(c.Middleware())
+}
+
+// Clear removes everything in the cache.
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to remove cache directory %s: %w", c.dir, err)
+	}
+	return nil
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenDir is the directory responses for the token used by `req` are kept
+// in.
+func (c *Cache) tokenDir(req *http.Request) string {
+	return filepath.Join(c.dir, hash(req.Header.Get("Authorization"))[:16])
+}
+
+func (c *Cache) path(req *http.Request) string {
+	return filepath.Join(c.tokenDir(req), hash(req.URL.String())+".json")
+}
+
+func (c *Cache) load(req *http.Request) (entry, bool) {
+	contents, err := ioutil.ReadFile(c.path(req))
+	if err != nil {
+		return entry{}, false
+	}
+	var e entry
+	// A corrupt entry is just a miss.
+	if err := json.Unmarshal(contents, &e); err != nil || e.URL != req.URL.String() {
+		return entry{}, false
+	}
+	return e, true
+}
+
+func (c *Cache) store(req *http.Request, e entry) error {
+	contents, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	if err := os.MkdirAll(c.tokenDir(req), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	// Write to a temporary file and rename it so a concurrent reader never
+	// sees half an entry.
+	tmp, err := ioutil.TempFile(c.tokenDir(req), "entry")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), c.path(req))
+}
+
+// invalidate removes all the cached responses for the token used by `req`.
+func (c *Cache) invalidate(req *http.Request) {
+	os.RemoveAll(c.tokenDir(req))
+}
+
+type transport struct {
+	cache *Cache
+	rt    http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := t.rt.RoundTrip(req)
+		// Anything that isn't a GET might change what we've cached, e.g.
+		// registering a webhook, so start again.
+		if err == nil && resp.StatusCode < 400 {
+			t.cache.invalidate(req)
+		}
+		return resp, err
+	}
+
+	ttl, ok := t.cache.ttls[upngo.Endpoint(req)]
+	if !ok || ttl <= 0 {
+		return t.rt.RoundTrip(req)
+	}
+
+	cached, ok := t.cache.load(req)
+	if ok && t.cache.now().Sub(cached.StoredAt) < ttl {
+		return cached.response(req), nil
+	}
+
+	// The cached response is stale, but if it has validators we can ask the
+	// API whether it has changed rather than fetching it again.
+	if ok {
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		cached.StoredAt = t.cache.now()
+		// Failing to refresh the entry only means it's revalidated again
+		// next time.
+		_ = t.cache.store(req, cached)
+		return cached.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// Failing to cache the response shouldn't fail the request.
+	_ = t.cache.store(req, entry{
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		StoredAt:   t.cache.now(),
+	})
+	return resp, nil
+}
+
+// response turns the entry back into a response to `req`.
+func (e entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+type testServer struct {
+	*httptest.Server
+	calls       map[string]int
+	conditional int
+	etag        string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	s := &testServer{calls: make(map[string]int), etag: `"v1"`}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		s.calls[req.Method+" "+req.URL.Path]++
+		if req.Method != http.MethodGet {
+			rw.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if req.Header.Get("If-None-Match") != "" {
+			s.conditional++
+			if req.Header.Get("If-None-Match") == s.etag {
+				rw.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		rw.Header().Set("ETag", s.etag)
+		body, err := json.Marshal(upngo.CategoriesResponse{Data: []upngo.CategoryResource{{Type: "categories", ID: "good-life"}}})
+		require.NoError(t, err)
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(body)
+	}))
+	return s
+}
+
+func newTestCache(t *testing.T) (*Cache, *time.Time, func()) {
+	dir, err := ioutil.TempDir("", "upngo-cache")
+	require.NoError(t, err)
+
+	now := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	c := New(dir, nil)
+	c.now = func() time.Time { return now }
+	return c, &now, func() { os.RemoveAll(dir) }
+}
+
+func get(t *testing.T, client *http.Client, token, url string) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	_, err = ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestCache(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c, now, cleanup := newTestCache(t)
+	defer cleanup()
+	client := &http.Client{Transport: c.Middleware()(http.DefaultTransport)}
+
+	url := server.URL + "/api/v1/categories"
+	require.Equal(t, http.StatusOK, get(t, client, "token", url).StatusCode)
+	require.Equal(t, http.StatusOK, get(t, client, "token", url).StatusCode)
+	require.Equal(t, 1, server.calls["GET /api/v1/categories"])
+
+	// Another token doesn't get the cached response.
+	get(t, client, "other", url)
+	require.Equal(t, 2, server.calls["GET /api/v1/categories"])
+
+	// Once it's stale it's revalidated with the ETag.
+	*now = now.Add(25 * time.Hour)
+	resp := get(t, client, "token", url)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `"v1"`, resp.Header.Get("ETag"))
+	require.Equal(t, 1, server.conditional)
+	require.Equal(t, 3, server.calls["GET /api/v1/categories"])
+
+	// Revalidating makes it fresh again.
+	get(t, client, "token", url)
+	require.Equal(t, 3, server.calls["GET /api/v1/categories"])
+
+	// If it has changed, the new response is used.
+	server.etag = `"v2"`
+	*now = now.Add(25 * time.Hour)
+	require.Equal(t, `"v2"`, get(t, client, "token", url).Header.Get("ETag"))
+	require.Equal(t, 4, server.calls["GET /api/v1/categories"])
+}
+
+func TestCacheSkipsAndInvalidates(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c, _, cleanup := newTestCache(t)
+	defer cleanup()
+	client := &http.Client{Transport: c.Middleware()(http.DefaultTransport)}
+
+	// Transactions aren't cached.
+	get(t, client, "token", server.URL+"/api/v1/transactions")
+	get(t, client, "token", server.URL+"/api/v1/transactions")
+	require.Equal(t, 2, server.calls["GET /api/v1/transactions"])
+
+	// Changing anything invalidates everything cached for the token.
+	webhooks := server.URL + "/api/v1/webhooks"
+	get(t, client, "token", webhooks)
+	req, err := http.NewRequest(http.MethodDelete, webhooks+"/id", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	get(t, client, "token", webhooks)
+	require.Equal(t, 2, server.calls["GET /api/v1/webhooks"])
+
+	require.NoError(t, c.Clear())
+	get(t, client, "token", webhooks)
+	require.Equal(t, 3, server.calls["GET /api/v1/webhooks"])
+}
+
+func TestCacheWithClient(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c, _, cleanup := newTestCache(t)
+	defer cleanup()
+
+	client := upngo.NewClient("token", upngo.WithBaseURL(server.URL), c.Option())
+	for i := 0; i < 2; i++ {
+		categories, err := client.Categories.List()
+		require.NoError(t, err)
+		require.Equal(t, "good-life", categories.Data[0].ID)
+	}
+	require.Equal(t, 1, server.calls["GET /api/v1/categories"])
+}
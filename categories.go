@@ -0,0 +1,121 @@
+package upngo
+
+import "net/http"
+
+type CategoryAttributes struct {
+	Name string `json:"name"`
+}
+
+type CategoryChildrenObject struct {
+	Data  []DataObject       `json:"data"`
+	Links RelatedLinksObject `json:"links"`
+}
+
+type CategoryRelationships struct {
+	Parent   OptionalRelationshipObject `json:"parent"`
+	Children CategoryChildrenObject     `json:"children"`
+}
+
+// CategoryResource is one of UpBank's transaction categories. Categories are
+// two levels deep, so a category either has a parent or children, not both.
+type CategoryResource struct {
+	Type          string                `json:"type"`
+	ID            string                `json:"id"`
+	Attributes    CategoryAttributes    `json:"attributes"`
+	Relationships CategoryRelationships `json:"relationships"`
+	Links         SelfLinkObject        `json:"links"`
+}
+
+// CategoriesResponse represents a response from the categories endpoint. It
+// isn't paginated so there are no links.
+type CategoriesResponse struct {
+	Data []CategoryResource `json:"data"`
+}
+
+// CategoryResponse represents a response from the category endpoint.
+type CategoryResponse struct {
+	Data CategoryResource `json:"data"`
+}
+
+// CategoriesClient is a Client's categories endpoints, e.g.
+// `client.Categories.Get(id)`. Calling it lists categories, like List, so code
+// from before the endpoints were grouped, `client.Categories()`, still works.
+//
+// Create one with NewCategoriesClient, otherwise its methods return
+// ErrNoImplementation.
+type CategoriesClient func(options ...CategoriesOption) (CategoriesResponse, error)
+
+// NewCategoriesClient creates a CategoriesClient that uses `api`, e.g. to
+// replace a Client's Categories with a mock.
+func NewCategoriesClient(api CategoriesAPI) CategoriesClient {
+	return func(options ...CategoriesOption) (CategoriesResponse, error) {
+		if len(options) == 1 && provide(options[0], api) {
+			return CategoriesResponse{}, nil
+		}
+		return api.List(options...)
+	}
+}
+
+// api is the implementation the sub-client wraps. Only sub-clients created
+// with NewCategoriesClient have one.
+func (c CategoriesClient) api() (CategoriesAPI, error) {
+	if c == nil {
+		return nil, ErrNoImplementation
+	}
+	api, ok := unwrap(func(request implementation) { c(request) }).(CategoriesAPI)
+	if !ok {
+		return nil, ErrNoImplementation
+	}
+	return api, nil
+}
+
+// List lists all the categories transactions can be in.
+func (c CategoriesClient) List(options ...CategoriesOption) (CategoriesResponse, error) {
+	if c == nil {
+		return CategoriesResponse{}, ErrNoImplementation
+	}
+	return c(options...)
+}
+
+// Get retrieves a category by its ID.
+func (c CategoriesClient) Get(id string) (CategoryResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return CategoryResponse{}, err
+	}
+	return api.Get(id)
+}
+
+// CategoriesService talks to the categories endpoints. It's what a Client's
+// Categories uses unless it's been replaced.
+type CategoriesService struct {
+	client *Client
+}
+
+// List lists all the categories transactions can be in.
+func (s *CategoriesService) List(options ...CategoriesOption) (CategoriesResponse, error) {
+	query := make([]Option, len(options))
+	for i, option := range options {
+		query[i] = option
+	}
+
+	var categoriesResponse CategoriesResponse
+	err := s.client.do(request{
+		name:    "get categories",
+		method:  http.MethodGet,
+		url:     s.client.buildURL("categories"),
+		options: query,
+	}, &categoriesResponse)
+	return categoriesResponse, err
+}
+
+// Get retrieves a category by its ID.
+func (s *CategoriesService) Get(id string) (CategoryResponse, error) {
+	var categoryResponse CategoryResponse
+	err := s.client.do(request{
+		name:   "get category by ID",
+		method: http.MethodGet,
+		url:    s.client.buildURL("categories", id),
+	}, &categoryResponse)
+	return categoryResponse, err
+}
@@ -0,0 +1,328 @@
+// Package chart draws charts on the terminal: sparklines, bar charts and line
+// charts. They're drawn with Unicode block characters, or plain ASCII for
+// terminals that can't show them, and can be coloured with ANSI escape codes.
+package chart
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultWidth is how wide charts are if Options doesn't say.
+const DefaultWidth = 80
+
+// DefaultHeight is how many rows line charts take up if Options doesn't say.
+const DefaultHeight = 10
+
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorReset = "\x1b[0m"
+)
+
+// Options changes how charts are drawn. The zero value draws an uncoloured
+// Unicode chart DefaultWidth columns wide.
+type Options struct {
+	// Width is how many columns the whole chart, labels and all, can take up.
+	Width int
+	// Height is how many rows a line chart's plot takes up.
+	Height int
+	// Color colours positive values green and negative values red. It should
+	// be off when the output isn't a terminal, otherwise the escape codes end
+	// up in files and pipes.
+	Color bool
+	// ASCII only uses ASCII characters.
+	ASCII bool
+	// Format formats values for labels. It defaults to printing the integer.
+	Format func(int64) string
+}
+
+func (o Options) width() int {
+	if o.Width <= 0 {
+		return DefaultWidth
+	}
+	return o.Width
+}
+
+func (o Options) height() int {
+	if o.Height <= 0 {
+		return DefaultHeight
+	}
+	return o.Height
+}
+
+func (o Options) format(value int64) string {
+	if o.Format == nil {
+		return strconv.FormatInt(value, 10)
+	}
+	return o.Format(value)
+}
+
+// colorize wraps `s` in the colour for `value`, if colouring is on.
+func (o Options) colorize(s string, value int64) string {
+	if !o.Color || s == "" {
+		return s
+	}
+	color := colorGreen
+	if value < 0 {
+		color = colorRed
+	}
+	return color + s + colorReset
+}
+
+// Point is a labelled value to chart, e.g. a category's spending or a day's
+// balance.
+type Point struct {
+	Label string
+	Value int64
+}
+
+var (
+	sparkUnicode = []rune("▁▂▃▄▅▆▇█")
+	sparkASCII   = []rune("_.-~=+*#")
+)
+
+// Sparkline draws `values` as a single line of characters whose heights
+// follow the values. If there are more values than fit in the width, they're
+// sampled evenly.
+func Sparkline(values []int64, options Options) string {
+	levels := sparkUnicode
+	if options.ASCII {
+		levels = sparkASCII
+	}
+
+	values = sample(values, options.width())
+	min, max := bounds(values)
+	var b strings.Builder
+	for _, value := range values {
+		b.WriteRune(levels[scale(value, min, max, len(levels))])
+	}
+	return b.String()
+}
+
+// Bars draws a horizontal bar for each point, with its label on the left and
+// its formatted value on the right. Bar lengths are relative to the largest
+// value. Negative values are drawn in red if colour is on, and with a
+// different character either way so they can be told apart when piped.
+func Bars(w io.Writer, points []Point, options Options) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	labelWidth, valueWidth := 0, 0
+	var maxAbs int64
+	for _, point := range points {
+		labelWidth = maxInt(labelWidth, utf8.RuneCountInString(point.Label))
+		valueWidth = maxInt(valueWidth, utf8.RuneCountInString(options.format(point.Value)))
+		maxAbs = maxInt64(maxAbs, abs(point.Value))
+	}
+	// Long labels shouldn't squash the bars to nothing.
+	labelWidth = minInt(labelWidth, options.width()/3)
+	barWidth := maxInt(options.width()-labelWidth-valueWidth-2, 1)
+
+	for _, point := range points {
+		bar := drawBar(abs(point.Value), maxAbs, barWidth, point.Value < 0, options.ASCII)
+		_, err := fmt.Fprintf(
+			w,
+			"%s %s%s %*s\n",
+			pad(truncate(point.Label, labelWidth), labelWidth),
+			options.colorize(bar, point.Value),
+			strings.Repeat(" ", barWidth-utf8.RuneCountInString(bar)),
+			valueWidth,
+			options.format(point.Value),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eighths are the partial blocks used to draw the end of a bar more finely
+// than a whole character.
+var eighths = []rune(" ▏▎▍▌▋▊▉")
+
+// drawBar draws a bar `value/max` of `width` long.
+func drawBar(value, max int64, width int, negative, ascii bool) string {
+	if max == 0 {
+		return ""
+	}
+	// In eighths of a character.
+	length := int(value * int64(width) * 8 / max)
+
+	switch {
+	case ascii && negative:
+		return strings.Repeat("-", length/8)
+	case ascii:
+		return strings.Repeat("#", length/8)
+	case negative:
+		return strings.Repeat("░", length/8)
+	}
+	bar := strings.Repeat("█", length/8)
+	if length%8 != 0 {
+		bar += string(eighths[length%8])
+	}
+	return bar
+}
+
+// Line draws the points as a line chart with the value on the y axis and the
+// first and last labels under the x axis. If there are more points than fit
+// in the width, they're sampled evenly. If there are only a few, each is
+// stretched over several columns.
+func Line(w io.Writer, points []Point, options Options) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	values := make([]int64, len(points))
+	for i, point := range points {
+		values[i] = point.Value
+	}
+	min, max := bounds(values)
+	height := options.height()
+
+	// The y axis labels are the maximum, middle and minimum values. A flat
+	// line is drawn in the middle so it only needs the one.
+	labels := map[int]string{(height - 1) / 2: options.format(min + (max-min)/2)}
+	if max != min {
+		labels[height-1] = options.format(max)
+		labels[0] = options.format(min)
+	}
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = maxInt(labelWidth, utf8.RuneCountInString(label))
+	}
+
+	plotWidth := maxInt(options.width()-labelWidth-2, 1)
+	values = sample(values, plotWidth)
+	stretch := maxInt(plotWidth/len(values), 1)
+	columns := len(values) * stretch
+
+	point, vertical, tick, axis, corner, horizontal := "•", "│", "┤", "│", "└", "─"
+	if options.ASCII {
+		point, vertical, tick, axis, corner, horizontal = "*", "|", "+", "|", "+", "-"
+	}
+
+	grid := make([][]string, height)
+	for row := range grid {
+		grid[row] = make([]string, columns)
+		for column := range grid[row] {
+			grid[row][column] = " "
+		}
+	}
+	previous := -1
+	for i, value := range values {
+		row := scale(value, min, max, height)
+		for column := i * stretch; column < (i+1)*stretch; column++ {
+			grid[row][column] = options.colorize(point, value)
+		}
+		// Join it to the previous point so the line doesn't have gaps.
+		if previous >= 0 {
+			for between := minInt(previous, row) + 1; between < maxInt(previous, row); between++ {
+				grid[between][i*stretch] = options.colorize(vertical, value)
+			}
+		}
+		previous = row
+	}
+
+	for row := height - 1; row >= 0; row-- {
+		label, ok := labels[row]
+		edge := axis
+		if ok {
+			edge = tick
+		}
+		_, err := fmt.Fprintf(w, "%*s %s%s\n", labelWidth, label, edge, strings.TrimRight(strings.Join(grid[row], ""), " "))
+		if err != nil {
+			return err
+		}
+	}
+
+	first, last := points[0].Label, points[len(points)-1].Label
+	gap := maxInt(columns-utf8.RuneCountInString(first)-utf8.RuneCountInString(last), 1)
+	_, err := fmt.Fprintf(
+		w,
+		"%s %s%s\n%s  %s%s%s\n",
+		strings.Repeat(" ", labelWidth), corner, strings.Repeat(horizontal, columns),
+		strings.Repeat(" ", labelWidth), first, strings.Repeat(" ", gap), last,
+	)
+	return err
+}
+
+// sample picks `n` values spread evenly through `values`, always including
+// the last. If there are `n` or fewer it returns them all.
+func sample(values []int64, n int) []int64 {
+	if len(values) <= n {
+		return values
+	}
+	sampled := make([]int64, n)
+	for i := range sampled {
+		sampled[i] = values[(i+1)*len(values)/n-1]
+	}
+	return sampled
+}
+
+func bounds(values []int64) (min, max int64) {
+	for i, value := range values {
+		if i == 0 || value < min {
+			min = value
+		}
+		if i == 0 || value > max {
+			max = value
+		}
+	}
+	return min, max
+}
+
+// scale maps `value` between `min` and `max` onto one of `levels` levels.
+func scale(value, min, max int64, levels int) int {
+	if max == min {
+		return (levels - 1) / 2
+	}
+	return int((value - min) * int64(levels-1) / (max - min))
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,62 @@
+package chart
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparkline(t *testing.T) {
+	require.Equal(t, "▁▂▃▄▅▆▇█", Sparkline([]int64{0, 1, 2, 3, 4, 5, 6, 7}, Options{}))
+	require.Equal(t, "_.-~=+*#", Sparkline([]int64{0, 1, 2, 3, 4, 5, 6, 7}, Options{ASCII: true}))
+	// Flat lines are drawn in the middle.
+	require.Equal(t, "▄▄▄", Sparkline([]int64{5, 5, 5}, Options{}))
+	// Too many values are sampled down to the width, keeping the last.
+	require.Equal(t, "▁█", Sparkline([]int64{0, 1, 2, 7}, Options{Width: 2}))
+}
+
+func TestBars(t *testing.T) {
+	var out bytes.Buffer
+	points := []Point{{"Food", 40}, {"Rent", 80}, {"Salary income", -20}}
+	require.NoError(t, Bars(&out, points, Options{Width: 20, ASCII: true}))
+	require.Equal(t, strings.Join([]string{
+		"Food   ####       40",
+		"Rent   #########  80",
+		"Salar… --        -20",
+		"",
+	}, "\n"), out.String())
+
+	out.Reset()
+	require.NoError(t, Bars(&out, []Point{{"Food", 3}, {"Rent", 16}}, Options{Width: 14}))
+	require.Equal(t, "Food █▏      3\nRent ██████ 16\n", out.String())
+
+	out.Reset()
+	require.NoError(t, Bars(&out, []Point{{"In", 1}, {"Out", -1}}, Options{Width: 10, Color: true}))
+	require.Contains(t, out.String(), colorGreen+"███"+colorReset)
+	require.Contains(t, out.String(), colorRed+"░░░"+colorReset)
+}
+
+func TestLine(t *testing.T) {
+	var out bytes.Buffer
+	points := []Point{{"start", 0}, {"b", 4}, {"c", 2}, {"end", 4}}
+	require.NoError(t, Line(&out, points, Options{Width: 11, Height: 5, ASCII: true}))
+	require.Equal(t, strings.Join([]string{
+		"4 +  **  **",
+		"  |  | | |",
+		"2 +  | **",
+		"  |  |",
+		"0 +**",
+		"  +--------",
+		"   start end",
+		"",
+	}, "\n"), out.String())
+}
+
+func TestLineFlat(t *testing.T) {
+	var out bytes.Buffer
+	points := []Point{{"a", 7}, {"b", 7}}
+	require.NoError(t, Line(&out, points, Options{Width: 7, Height: 3, ASCII: true}))
+	require.Equal(t, "  |\n7 +****\n  |\n  +----\n   a  b\n", out.String())
+}
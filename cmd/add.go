@@ -4,11 +4,13 @@ import (
 	"fmt"
 
 	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/keyring"
 	"github.com/spf13/cobra"
 )
 
 var (
 	webhookDescription string
+	webhookSaveSecret  bool
 )
 
 // addCmd represents the add command
@@ -24,8 +26,8 @@ var addWebhookCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		url := args[0]
 		token := getToken()
-		client := upngo.NewClient(token)
-		webhook, err := client.RegisterWebhook(url, upngo.WithDescription(webhookDescription))
+		client := newClient(token)
+		webhook, err := client.Webhooks.Create(url, upngo.WithDescription(webhookDescription))
 		if err != nil {
 			abort("Failed to register webhook at %s: %v", url, err)
 		}
@@ -37,6 +39,13 @@ Here's the secret key:
 \t%s
 Use it to verify requests send to the webhook URL.
 `, url, webhook.Data.Attributes.SecretKey)
+
+		if webhookSaveSecret {
+			if err := keyring.SetWebhookSecret(webhook.Data.ID, webhook.Data.Attributes.SecretKey); err != nil {
+				abort("Failed to save secret key of webhook %s to keyring: %v", webhook.Data.ID, err)
+			}
+			fmt.Printf("It has been saved to your keyring, get it again with 'upngo webhook secret %s'.\n", webhook.Data.ID)
+		}
 	},
 }
 
@@ -51,4 +60,5 @@ func init() {
 		"",
 		"Webhook description (optional)",
 	)
+	addWebhookCmd.Flags().BoolVar(&webhookSaveSecret, "save-secret", false, "Save the webhook's secret key in the keyring")
 }
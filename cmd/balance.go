@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/chart"
+	"github.com/nick96/upngo/keyring"
+	"github.com/nick96/upngo/networth"
+	"github.com/nick96/upngo/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	balanceAccount string
+	balanceSince   string
+	balanceChart   bool
+)
+
+// balanceCmd represents the balance command
+var balanceCmd = &cobra.Command{
+	Use:   "balance",
+	Short: "Show account balances and net worth over time.",
+	Long: `Show account balances and net worth over time.
+
+The API only gives the current balance of each account, so past balances are
+worked out from the transactions since. They're recorded in the local store
+each time you run upngo sync, so sync first. The first sync after upgrading
+works out each account's whole history, which needs every transaction, so run
+upngo sync --full if the store was synced before balances were recorded.`,
+}
+
+var balanceHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the daily balance of each account.",
+	Run: func(cmd *cobra.Command, args []string) {
+		since := balanceSinceDay()
+		s := openStore()
+		defer s.Close()
+
+		accounts, err := s.Accounts()
+		if err != nil {
+			abort("Failed to get accounts from local store: %v", err)
+		}
+		if accountID := stringSetting(cmd, "account", configKeyAccount); accountID != "" {
+			account, err := s.Account(accountID)
+			if errors.Is(err, store.ErrNotFound) {
+				abort("Account %s isn't in the local store, run upngo sync first", accountID)
+			} else if err != nil {
+				abort("Failed to get account from local store: %v", err)
+			}
+			accounts = []upngo.AccountResource{account}
+		}
+
+		var names []string
+		var series []networth.Series
+		for _, account := range accounts {
+			balances, err := s.Balances(account.ID, since)
+			if err != nil {
+				abort("Failed to get balances from local store: %v", err)
+			}
+			names = append(names, account.Attributes.DisplayName)
+			series = append(series, balances)
+		}
+		showBalances(names, series)
+	},
+}
+
+var balanceNetWorthCmd = &cobra.Command{
+	Use:   "networth",
+	Short: "Show the total balance of every account in every profile.",
+	Long: `Show the total balance of every account in every profile.
+
+Balances are read from the local store of each profile that has one, so sync
+each profile first. Accounts that are in more than one profile, like a 2Up
+account, are only counted once.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		since := balanceSinceDay()
+		profiles := storeProfiles()
+		if len(profiles) == 0 {
+			abort("No local stores found, run upngo sync first")
+		}
+
+		seen := make(map[string]bool)
+		var series []networth.Series
+		for _, profile := range profiles {
+			s, err := store.Open(storePathFor(profile))
+			if err != nil {
+				abort("Failed to open local store for profile %s: %v", profile, err)
+			}
+			accounts, err := s.Accounts()
+			if err != nil {
+				s.Close()
+				abort("Failed to get accounts for profile %s: %v", profile, err)
+			}
+			for _, account := range accounts {
+				if seen[account.ID] {
+					continue
+				}
+				seen[account.ID] = true
+
+				balances, err := s.Balances(account.ID, since)
+				if err != nil {
+					s.Close()
+					abort("Failed to get balances for profile %s: %v", profile, err)
+				}
+				series = append(series, balances)
+			}
+			s.Close()
+		}
+
+		total, err := networth.Combine(series...)
+		if err != nil {
+			abort("Failed to add up balances: %v", err)
+		}
+		showBalances([]string{"Net worth"}, []networth.Series{total})
+	},
+}
+
+// balanceSinceDay is the first day to show balances for. It defaults to 30
+// days ago.
+func balanceSinceDay() time.Time {
+	since := parseDate(balanceSince)
+	if since.IsZero() {
+		since = time.Now().AddDate(0, 0, -30)
+	}
+	return networth.Day(since, location())
+}
+
+// storeProfiles lists the profiles that have a local store.
+func storeProfiles() []string {
+	defaultPath := storePathFor(keyring.DefaultProfile)
+	paths, err := filepath.Glob(filepath.Join(filepath.Dir(defaultPath), "*.db"))
+	if err != nil {
+		abort("Failed to find local stores: %v", err)
+	}
+
+	var profiles []string
+	for _, path := range paths {
+		if path == defaultPath {
+			profiles = append(profiles, keyring.DefaultProfile)
+		} else {
+			profiles = append(profiles, strings.TrimSuffix(filepath.Base(path), ".db"))
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// showBalances shows the balances as a chart if --chart was given, otherwise
+// as a table.
+func showBalances(names []string, series []networth.Series) {
+	if balanceChart {
+		chartBalances(names, series)
+	} else {
+		printBalances(names, series)
+	}
+}
+
+// chartBalances draws a line chart of each series of balances, headed by its
+// name, a sparkline and its latest balance.
+func chartBalances(names []string, series []networth.Series) {
+	drawn := false
+	for i, s := range series {
+		if len(s.Points) == 0 {
+			continue
+		}
+		if drawn {
+			fmt.Println()
+		}
+		drawn = true
+
+		options := chartOptions(s.Currency)
+		var values []int64
+		var points []chart.Point
+		for _, point := range s.Points {
+			values = append(values, point.Balance)
+			points = append(points, chart.Point{Label: point.Date.Format(dateLayout()), Value: point.Balance})
+		}
+
+		latest := options.Format(values[len(values)-1])
+		sparkOptions := options
+		sparkOptions.Width = options.Width - utf8.RuneCountInString(names[i]) - len(latest) - 4
+		// A long name on a narrow terminal leaves no room for the sparkline.
+		if sparkOptions.Width < 1 {
+			fmt.Printf("%s  %s\n\n", names[i], latest)
+		} else {
+			fmt.Printf("%s  %s  %s\n\n", names[i], chart.Sparkline(values, sparkOptions), latest)
+		}
+		if err := chart.Line(os.Stdout, points, options); err != nil {
+			abort("Failed to draw balance chart: %v", err)
+		}
+	}
+}
+
+// printBalances prints a table with a row for each day and a column for each
+// series of balances. Days a series has no balance for are left blank.
+func printBalances(names []string, series []networth.Series) {
+	days := make(map[time.Time]bool)
+	balances := make([]map[time.Time]int64, len(series))
+	for i, s := range series {
+		balances[i] = make(map[time.Time]int64)
+		for _, point := range s.Points {
+			days[point.Date] = true
+			balances[i][point.Date] = point.Balance
+		}
+	}
+	var dates []time.Time
+	for day := range days {
+		dates = append(dates, day)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(writer, "Date\t%s\t\n", strings.Join(names, "\t"))
+	for _, day := range dates {
+		// Days are at midnight UTC so they're formatted as they are, not in
+		// the configured timezone.
+		row := []string{day.Format(dateLayout())}
+		for i, s := range series {
+			value := ""
+			if balance, ok := balances[i][day]; ok {
+				value = upngo.FormatBaseUnits(balance, s.Currency)
+			}
+			row = append(row, value)
+		}
+		fmt.Fprintf(writer, "%s\t\n", strings.Join(row, "\t"))
+	}
+	writer.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(balanceCmd)
+	balanceCmd.AddCommand(balanceHistoryCmd, balanceNetWorthCmd)
+
+	balanceCmd.PersistentFlags().StringVar(&balanceSince, "since", "", "Show balances from this date (YYYY-MM-DD) (default 30 days ago)")
+	balanceCmd.PersistentFlags().BoolVar(&balanceChart, "chart", false, "Draw the balances as line charts instead of a table")
+	balanceHistoryCmd.Flags().StringVarP(&balanceAccount, "account", "a", "", "Only show the balance of the account with this ID (default is the account setting, or all accounts)")
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/budget"
+	"github.com/nick96/upngo/store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// budgetAlertExitCode is the exit code of `budget show` when a budget has
+// crossed one of its thresholds. It's different to the usual failure exit
+// code so scripts can tell an alert apart from an error.
+const budgetAlertExitCode = 2
+
+var (
+	budgetCategory   string
+	budgetTag        string
+	budgetAmount     string
+	budgetPeriod     string
+	budgetThresholds []int
+	budgetOffline    bool
+)
+
+// budgetCmd represents the budget command
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Set and track budgets for categories or tags.",
+}
+
+var budgetSetCmd = &cobra.Command{
+	Use:   "set [NAME]",
+	Short: "Create or update a budget.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Viper treats dots as nesting and lowercases keys so we do the
+		// same up front to avoid surprises.
+		name := strings.ToLower(args[0])
+		if strings.Contains(name, ".") {
+			abort("Budget names can't contain '.'")
+		}
+
+		b := budget.Budget{
+			Category:   budgetCategory,
+			Tag:        budgetTag,
+			Amount:     budgetAmount,
+			Period:     budget.Period(budgetPeriod),
+			Thresholds: budgetThresholds,
+		}
+		if err := b.Validate(); err != nil {
+			abort("Invalid budget: %v", err)
+		}
+
+		setConfig(profileKey("budgets."+name), b)
+		fmt.Printf("Saved budget %s 💰\n", name)
+	},
+}
+
+var budgetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List budgets.",
+	Run: func(cmd *cobra.Command, args []string) {
+		budgets := loadBudgets()
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		for _, name := range budgetNames(budgets) {
+			b := budgets[name]
+			target := "category " + b.Category
+			if b.Tag != "" {
+				target = "tag " + b.Tag
+			}
+			period := b.Period
+			if period == "" {
+				period = budget.PeriodMonthly
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", name, target, b.Amount, period)
+		}
+		writer.Flush()
+	},
+}
+
+var budgetShowCmd = &cobra.Command{
+	Use:   "show [NAME...]",
+	Short: "Show how spending is tracking against budgets.",
+	Long: `Show how spending is tracking against budgets.
+
+Shows all budgets unless some names are given. If any budget has crossed one of
+its alert thresholds then an alert is printed to stderr and the exit code is 2,
+which makes it easy to run from cron.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		budgets := loadBudgets()
+		names := args
+		if len(names) == 0 {
+			names = budgetNames(budgets)
+		}
+		if len(names) == 0 {
+			abort("No budgets set. Add one with upngo budget set")
+		}
+
+		// Periods start at midnight in the configured timezone.
+		now := time.Now().In(location())
+		var since time.Time
+		for i, name := range names {
+			b, ok := budgets[strings.ToLower(name)]
+			if !ok {
+				abort("No budget called %s", name)
+			}
+			if start, _ := b.Bounds(now); i == 0 || start.Before(since) {
+				since = start
+			}
+		}
+
+		var transactions []upngo.TransactionResource
+		if budgetOffline {
+			s := openStore()
+			defer s.Close()
+
+			var err error
+			transactions, err = s.Transactions(store.TransactionFilter{Since: since})
+			if err != nil {
+				abort("Failed to get upbank transactions: %v", err)
+			}
+		} else {
+			token := getToken()
+			client := newClient(token)
+			transactions = fetchTransactions(client, "", transactionFilters(since, time.Time{})...)
+		}
+
+		alerting := false
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintf(writer, "Budget\tSpent\tLimit\tRemaining\tUsed\tProjected\tResets\n")
+		for _, name := range names {
+			name = strings.ToLower(name)
+			status, err := budget.Evaluate(name, budgets[name], transactions, now)
+			if err != nil {
+				abort("Failed to evaluate budget %s: %v", name, err)
+			}
+
+			currency := status.Budget.Currency
+			fmt.Fprintf(
+				writer,
+				"%s\t%s\t%s\t%s\t%d%%\t%s\t%s\n",
+				name,
+				upngo.FormatBaseUnits(status.Spent, currency),
+				upngo.FormatBaseUnits(status.Limit, currency),
+				upngo.FormatBaseUnits(status.Remaining(), currency),
+				status.Percent(),
+				upngo.FormatBaseUnits(status.Projected, currency),
+				status.End.In(location()).Format(dateLayout()),
+			)
+
+			if status.Alerting() {
+				alerting = true
+				fmt.Fprintf(
+					os.Stderr,
+					"ALERT: budget %s has reached %d%% (%d%% used)\n",
+					name,
+					status.Crossed[len(status.Crossed)-1],
+					status.Percent(),
+				)
+			}
+		}
+		writer.Flush()
+
+		if alerting {
+			os.Exit(budgetAlertExitCode)
+		}
+	},
+}
+
+// loadBudgets loads the budgets from the config file.
+func loadBudgets() map[string]budget.Budget {
+	var budgets map[string]budget.Budget
+	if err := viper.UnmarshalKey(profileKey("budgets"), &budgets); err != nil {
+		abort("Failed to read budgets from config: %v", err)
+	}
+	return budgets
+}
+
+func budgetNames(budgets map[string]budget.Budget) []string {
+	var names []string
+	for name := range budgets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	rootCmd.AddCommand(budgetCmd)
+	budgetCmd.AddCommand(budgetSetCmd, budgetListCmd, budgetShowCmd)
+
+	budgetSetCmd.Flags().StringVarP(&budgetCategory, "category", "c", "", "Category ID the budget is for")
+	budgetSetCmd.Flags().StringVarP(&budgetTag, "tag", "t", "", "Tag the budget is for")
+	budgetSetCmd.Flags().StringVarP(&budgetAmount, "amount", "a", "", "Amount that can be spent each period, e.g. 500.00")
+	budgetSetCmd.Flags().StringVarP(&budgetPeriod, "period", "p", string(budget.PeriodMonthly), "How often the budget resets (weekly|monthly)")
+	budgetSetCmd.Flags().IntSliceVar(&budgetThresholds, "threshold", nil, "Percentage of the budget to alert at, can be given more than once (default 80,100)")
+	_ = budgetSetCmd.MarkFlagRequired("amount")
+
+	budgetShowCmd.Flags().BoolVar(&budgetOffline, "offline", false, "Use the local store (see upngo sync) instead of the API")
+}
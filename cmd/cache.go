@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of accounts, categories, tags and webhooks.",
+	Long: `Manage the cache of accounts, categories, tags and webhooks.
+
+Reference data that rarely changes is cached so it isn't fetched by every
+command. Give --no-cache to any command to skip the cache.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove everything from the cache.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := openCache().Clear(); err != nil {
+			abort("Failed to clear cache: %v", err)
+		}
+		fmt.Println("Cleared cache 🧹")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
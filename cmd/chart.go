@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/chart"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// chartOptions works out how to draw charts on stdout. They fill the width of
+// the terminal, or $COLUMNS if it's set. They're only coloured when stdout is
+// a terminal and $NO_COLOR isn't set, so escape codes don't end up in pipes
+// and files. Dumb terminals get plain ASCII. Amounts are formatted in
+// `currency`.
+func chartOptions(currency string) chart.Options {
+	fd := int(os.Stdout.Fd())
+	isTerminal := terminal.IsTerminal(fd)
+	_, noColor := os.LookupEnv("NO_COLOR")
+	dumb := os.Getenv("TERM") == "dumb"
+
+	options := chart.Options{
+		Width: chart.DefaultWidth,
+		Color: isTerminal && !noColor && !dumb,
+		ASCII: dumb,
+		Format: func(value int64) string {
+			return upngo.FormatBaseUnits(value, currency)
+		},
+	}
+	if isTerminal {
+		if width, _, err := terminal.GetSize(fd); err == nil && width > 0 {
+			options.Width = width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		options.Width = width
+	}
+	return options
+}
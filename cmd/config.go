@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/nick96/upngo/keyring"
+	"github.com/spf13/cast"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// Keys for settings in the config file. Each can also be set with an
+// environment variable by upper casing it, replacing '-' with '_' and adding
+// the prefix UPNGO_, e.g. UPNGO_PAGE_SIZE.
+const (
+	configKeyFormat     = "format"
+	configKeyAccount    = "account"
+	configKeyDateFormat = "date-format"
+	configKeyTimezone   = "timezone"
+	configKeyPageSize   = "page-size"
+	configKeyProfile    = "profile"
+
+	configKeyKeyringBackend = "keyring-backend"
+	configKeyKeyringFileDir = "keyring-file-dir"
+	configKeyKeyringPassDir = "keyring-pass-dir"
+)
+
+// configDefaults are the values settings take if they aren't set anywhere
+// else. They aren't registered with viper because it would write them into the
+// config file along with everything else.
+var configDefaults = map[string]interface{}{
+	configKeyFormat:     "table",
+	configKeyAccount:    "",
+	configKeyDateFormat: "2006-01-02",
+	configKeyTimezone:   "Local",
+	configKeyPageSize:   maxPageSize,
+	configKeyProfile:    "",
+
+	configKeyKeyringBackend: "",
+	configKeyKeyringFileDir: "",
+	configKeyKeyringPassDir: "",
+}
+
+// configPath is where the upngo config file lives. It respects
+// `XDG_CONFIG_HOME`, falling back to `~/.config`.
+func configPath() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			abort("Failed to find home directory: %v", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "upngo", "config.yaml")
+}
+
+// initConfig reads in the config file, if there is one, and sets up
+// environment variables.
+func initConfig() {
+	viper.SetEnvPrefix("upngo")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
+	viper.AutomaticEnv()
+
+	viper.SetConfigFile(configPath())
+	if err := viper.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		abort("Failed to read config file %s: %v", viper.ConfigFileUsed(), err)
+	}
+}
+
+// fileConfig reads just the config file into a fresh instance of viper.
+// Changes are written through it rather than the global instance because that
+// also has settings from environment variables, which would end up in the
+// file.
+func fileConfig() *viper.Viper {
+	v := viper.New()
+	v.SetConfigFile(viper.ConfigFileUsed())
+	if err := v.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		abort("Failed to read config file %s: %v", v.ConfigFileUsed(), err)
+	}
+	return v
+}
+
+// setConfig sets `key` to `value` in the config file, creating it if it
+// doesn't exist.
+func setConfig(key string, value interface{}) {
+	v := fileConfig()
+	v.Set(key, value)
+	writeConfig(v)
+	viper.Set(key, value)
+}
+
+// deleteConfigKeys removes `keys` from the config file. Viper can't unset a
+// key, so we copy everything else into a fresh instance and write that out
+// instead.
+func deleteConfigKeys(keys ...string) {
+	settings := fileConfig().AllSettings()
+	for _, key := range keys {
+		parts := strings.Split(strings.ToLower(key), ".")
+		section := settings
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := section[part].(map[string]interface{})
+			if !ok {
+				section = nil
+				break
+			}
+			section = next
+		}
+		if section != nil {
+			delete(section, parts[len(parts)-1])
+		}
+	}
+
+	v := viper.New()
+	v.SetConfigFile(viper.ConfigFileUsed())
+	if err := v.MergeConfigMap(settings); err != nil {
+		abort("Failed to update config: %v", err)
+	}
+	writeConfig(v)
+}
+
+// writeConfig writes `v` to the config file, creating it if it doesn't exist.
+func writeConfig(v *viper.Viper) {
+	path := v.ConfigFileUsed()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		abort("Failed to create config directory: %v", err)
+	}
+	if err := v.WriteConfig(); err != nil {
+		abort("Failed to write config file %s: %v", path, err)
+	}
+}
+
+// envVar is the environment variable that sets `key`.
+func envVar(key string) string {
+	return "UPNGO_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+}
+
+// settingKey is the key to look `key` up with. Profiles other than the default
+// can override settings in their own section of the config file, but an
+// environment variable still takes precedence over both.
+func settingKey(key string) string {
+	if _, ok := os.LookupEnv(envVar(key)); ok {
+		return key
+	}
+	if profiled := profileKey(key); profiled != key && viper.IsSet(profiled) {
+		return profiled
+	}
+	return key
+}
+
+// setting gets the value of the setting `key`, falling back to its default.
+func setting(key string) interface{} {
+	if key := settingKey(key); viper.IsSet(key) {
+		return viper.Get(key)
+	}
+	return configDefaults[key]
+}
+
+// stringSetting gets the setting `key`, preferring the value of the flag
+// `flag` on `cmd` if it was given. Several commands can share a setting so we
+// check the flag directly rather than binding it with viper, which only allows
+// one flag per key.
+func stringSetting(cmd *cobra.Command, flag, key string) string {
+	if f := cmd.Flags().Lookup(flag); f != nil && f.Changed {
+		return f.Value.String()
+	}
+	return cast.ToString(setting(key))
+}
+
+// dateLayout is the layout dates are given and shown in.
+func dateLayout() string {
+	return cast.ToString(setting(configKeyDateFormat))
+}
+
+// location is the timezone dates are interpreted and shown in.
+func location() *time.Location {
+	loc, err := time.LoadLocation(cast.ToString(setting(configKeyTimezone)))
+	if err != nil {
+		abort("Invalid timezone in config: %v", err)
+	}
+	return loc
+}
+
+// pageSize is the number of transactions to ask for in each request.
+func pageSize() int {
+	size := cast.ToInt(setting(configKeyPageSize))
+	if size < 1 || size > maxPageSize {
+		abort("Invalid page size %d, it must be between 1 and %d", size, maxPageSize)
+	}
+	return size
+}
+
+// configureKeyring sets up the keyring backend from the --keyring-backend flag
+// and config.
+func configureKeyring() {
+	backend := keyringBackendFlag
+	if backend == "" {
+		backend = cast.ToString(setting(configKeyKeyringBackend))
+	}
+
+	err := keyring.Configure(keyring.Options{
+		Backend: backend,
+		FileDir: cast.ToString(setting(configKeyKeyringFileDir)),
+		PassDir: cast.ToString(setting(configKeyKeyringPassDir)),
+	})
+	if err != nil {
+		abort("Failed to configure keyring: %v", err)
+	}
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Get and set configuration.",
+	Long: fmt.Sprintf(`Get and set configuration.
+
+Settings are taken from flags first, then UPNGO_* environment variables, then
+the config file and finally their defaults. The settings are:
+
+  %s  default output format for reports (table|json|csv)
+  %s  ID of the account to use when one isn't given
+  %s  Go time layout dates are given and shown in
+  %s  timezone dates are interpreted and shown in, e.g. Australia/Melbourne
+  %s  number of transactions to fetch per request (1-100)
+  %s  profile to use (see upngo profile)
+  %s  keyring backend to store tokens in (%s)
+  %s  directory the file keyring backend uses
+  %s  password store the pass keyring backend uses
+
+The file keyring backend is encrypted with a passphrase taken from %s
+or prompted for if that isn't set.
+
+When a profile other than the default is in use, settings are read from and
+written to its own section of the config file. Settings it doesn't have fall
+back to the top level.`,
+		configKeyFormat, configKeyAccount, configKeyDateFormat,
+		configKeyTimezone, configKeyPageSize, configKeyProfile,
+		configKeyKeyringBackend, strings.Join(keyring.Backends, "|"),
+		configKeyKeyringFileDir, configKeyKeyringPassDir,
+		keyring.PassphraseEnvVar,
+	),
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [KEY]",
+	Short: "Print the value of a setting.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key := strings.ToLower(args[0])
+		var value interface{}
+		if _, ok := configDefaults[key]; ok {
+			value = setting(key)
+		} else if viper.IsSet(key) {
+			value = viper.Get(key)
+		} else {
+			abort("Setting %s isn't set", args[0])
+		}
+		if _, ok := value.(map[string]interface{}); ok {
+			printYAML(value)
+			return
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [KEY] [VALUE]",
+	Short: "Set the value of a setting in the config file.",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.ToLower(args[0])
+		if _, ok := configDefaults[name]; !ok {
+			abort("Unknown setting %s, see upngo config --help for the available settings", name)
+		}
+
+		// Check the new value is usable so mistakes are found now rather
+		// than on the next command.
+		var value interface{} = args[1]
+		switch name {
+		case configKeyTimezone:
+			if _, err := time.LoadLocation(args[1]); err != nil {
+				abort("Invalid timezone: %v", err)
+			}
+		case configKeyPageSize:
+			size, err := strconv.Atoi(args[1])
+			if err != nil || size < 1 || size > maxPageSize {
+				abort("Invalid page size %s, it must be between 1 and %d", args[1], maxPageSize)
+			}
+			value = size
+		case configKeyKeyringBackend:
+			if err := keyring.Configure(keyring.Options{Backend: args[1]}); err != nil {
+				abort("Invalid keyring backend: %v", err)
+			}
+		case configKeyProfile:
+			if err := validateProfile(args[1]); err != nil {
+				abort("Invalid profile: %v", err)
+			}
+		}
+
+		key := name
+		if name != configKeyProfile {
+			key = profileKey(name)
+		}
+		setConfig(key, value)
+	},
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print all settings, including defaults.",
+	Run: func(cmd *cobra.Command, args []string) {
+		settings := viper.AllSettings()
+		for key := range configDefaults {
+			settings[key] = setting(key)
+		}
+		printYAML(settings)
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file.",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(viper.ConfigFileUsed())
+	},
+}
+
+func printYAML(value interface{}) {
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		abort("Failed to format config: %v", err)
+	}
+	fmt.Print(string(out))
+}
+
+// configKeys lists the known settings for completion.
+func configKeys() []string {
+	var keys []string
+	for key := range configDefaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd, configViewCmd, configPathCmd)
+
+	configGetCmd.ValidArgs = configKeys()
+	configSetCmd.ValidArgs = configKeys()
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// withConfigFile sets up viper with a config file containing `contents` and
+// returns its path.
+func withConfigFile(t *testing.T, contents string) (string, func()) {
+	dir, err := ioutil.TempDir("", "upngo-config")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "upngo", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+
+	previous, hadPrevious := os.LookupEnv("XDG_CONFIG_HOME")
+	require.NoError(t, os.Setenv("XDG_CONFIG_HOME", dir))
+	viper.Reset()
+	initConfig()
+
+	return path, func() {
+		viper.Reset()
+		if hadPrevious {
+			os.Setenv("XDG_CONFIG_HOME", previous)
+		} else {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+func readConfigFile(t *testing.T, path string) map[string]interface{} {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+	return v.AllSettings()
+}
+
+func TestSetConfigDoesNotWriteEnvironment(t *testing.T) {
+	require.NoError(t, os.Setenv("UPNGO_PROFILE", "joint"))
+	defer os.Unsetenv("UPNGO_PROFILE")
+	require.NoError(t, os.Setenv("UPNGO_PAGE_SIZE", "10"))
+	defer os.Unsetenv("UPNGO_PAGE_SIZE")
+
+	path, cleanup := withConfigFile(t, "profile: default\n")
+	defer cleanup()
+
+	setConfig(configKeyFormat, "json")
+	require.Equal(t, map[string]interface{}{
+		"profile": "default",
+		"format":  "json",
+	}, readConfigFile(t, path))
+
+	// The environment still wins for the rest of the run.
+	require.Equal(t, "joint", viper.GetString(configKeyProfile))
+	require.Equal(t, "json", viper.GetString(configKeyFormat))
+}
+
+func TestDeleteConfigKeysDoesNotWriteEnvironment(t *testing.T) {
+	require.NoError(t, os.Setenv("UPNGO_PROFILE", "joint"))
+	defer os.Unsetenv("UPNGO_PROFILE")
+
+	path, cleanup := withConfigFile(t, "profile: work\nformat: csv\nprofiles:\n  work:\n    format: json\n")
+	defer cleanup()
+
+	deleteConfigKeys("profiles.work", configKeyProfile)
+	require.Equal(t, map[string]interface{}{"format": "csv"}, readConfigFile(t, path))
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/export"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	exportAccount string
+	exportSince   string
+	exportUntil   string
+	exportOutput  string
+	exportMapping string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export transactions for use in personal finance tools.",
+}
+
+var exportOFXCmd = &cobra.Command{
+	Use:   "ofx",
+	Short: "Export transactions as OFX.",
+	Run: func(cmd *cobra.Command, args []string) {
+		runExport(cmd, export.WriteOFX)
+	},
+}
+
+var exportQIFCmd = &cobra.Command{
+	Use:   "qif",
+	Short: "Export transactions as QIF.",
+	Run: func(cmd *cobra.Command, args []string) {
+		runExport(cmd, export.WriteQIF)
+	},
+}
+
+var exportLedgerCmd = &cobra.Command{
+	Use:   "ledger",
+	Short: "Export transactions as a ledger journal (also readable by hledger).",
+	Run: func(cmd *cobra.Command, args []string) {
+		mapping := loadMapping(exportMapping)
+		runExport(cmd, func(w io.Writer, accounts []upngo.AccountResource, transactions []upngo.TransactionResource) error {
+			return export.WriteLedger(w, accounts, transactions, mapping)
+		})
+	},
+}
+
+var exportBeancountCmd = &cobra.Command{
+	Use:   "beancount",
+	Short: "Export transactions as a beancount journal.",
+	Run: func(cmd *cobra.Command, args []string) {
+		mapping := loadMapping(exportMapping)
+		runExport(cmd, func(w io.Writer, accounts []upngo.AccountResource, transactions []upngo.TransactionResource) error {
+			return export.WriteBeancount(w, accounts, transactions, mapping)
+		})
+	},
+}
+
+// loadMapping loads the account and category mapping for the plain-text
+// accounting exports. The file can be in any format viper understands. If no
+// path is given then the empty mapping is used, which falls back to the
+// defaults for everything.
+func loadMapping(path string) export.Mapping {
+	var mapping export.Mapping
+	if path == "" {
+		return mapping
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		abort("Failed to read mapping file %s: %v", path, err)
+	}
+	if err := v.Unmarshal(&mapping); err != nil {
+		abort("Failed to parse mapping file %s: %v", path, err)
+	}
+	return mapping
+}
+
+func runExport(cmd *cobra.Command, write func(io.Writer, []upngo.AccountResource, []upngo.TransactionResource) error) {
+	token := getToken()
+	client := newClient(token)
+
+	accountID := stringSetting(cmd, "account", configKeyAccount)
+	var accounts []upngo.AccountResource
+	if accountID == "" {
+		accounts = fetchAccounts(client)
+	} else {
+		accountResponse, err := client.Accounts.Get(accountID)
+		if err != nil {
+			abort("Failed to get account by ID %s: %v", accountID, err)
+		}
+		accounts = []upngo.AccountResource{accountResponse.Data}
+	}
+
+	options := transactionFilters(parseDate(exportSince), parseDate(exportUntil))
+	transactions := fetchTransactions(client, accountID, options...)
+
+	var out io.Writer = os.Stdout
+	if exportOutput != "" {
+		file, err := os.Create(exportOutput)
+		if err != nil {
+			abort("Failed to create %s: %v", exportOutput, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := write(out, accounts, transactions); err != nil {
+		abort("Failed to export transactions: %v", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportOFXCmd, exportQIFCmd, exportLedgerCmd, exportBeancountCmd)
+
+	exportCmd.PersistentFlags().StringVarP(&exportAccount, "account", "a", "", "Only export transactions from the account with this ID (default is the account setting)")
+	exportCmd.PersistentFlags().StringVar(&exportSince, "since", "", "Only export transactions on or after this date (YYYY-MM-DD)")
+	exportCmd.PersistentFlags().StringVar(&exportUntil, "until", "", "Only export transactions before this date (YYYY-MM-DD)")
+	exportCmd.PersistentFlags().StringVarP(&exportOutput, "output", "o", "", "File to write the export to (default stdout)")
+
+	for _, cmd := range []*cobra.Command{exportLedgerCmd, exportBeancountCmd} {
+		cmd.Flags().StringVarP(&exportMapping, "mapping", "m", "", "File mapping accounts and categories to journal accounts")
+	}
+}
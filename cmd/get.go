@@ -6,7 +6,6 @@ import (
 	"text/tabwriter"
 	"time"
 
-	"github.com/nick96/upngo"
 	"github.com/spf13/cobra"
 )
 
@@ -23,8 +22,8 @@ var getAccountCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		id := args[0]
 		token := getToken()
-		client := upngo.NewClient(token)
-		account, err := client.Account(id)
+		client := newClient(token)
+		account, err := client.Accounts.Get(id)
 		if err != nil {
 			abort("Error: failed to get account by ID %s: %v", id, err)
 		}
@@ -48,8 +47,8 @@ var getTransactionCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		id := args[0]
 		token := getToken()
-		client := upngo.NewClient(token)
-		transaction, err := client.Transaction(id)
+		client := newClient(token)
+		transaction, err := client.Transactions.Get(id)
 		if err != nil {
 			abort("Error: failed to get transaction by ID %s: %v", id, err)
 		}
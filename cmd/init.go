@@ -2,41 +2,64 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 
-	"github.com/nick96/upngo/keyring"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
+var initTokenFile string
+
 // initCmd represents the init command
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialise the UpBank CLI for ease of use by adding the token to your keyring.",
+	Long: `Initialise the UpBank CLI for ease of use by adding the token to your keyring.
+
+The token is prompted for if stdin is a terminal. Otherwise it's read from
+stdin, or from the file given with --token-file, so init can be used in
+scripts and containers. The token is checked with a ping before it's saved.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		token := readToken(initTokenFile)
+		saveToken(token)
+	},
+}
+
+// readToken reads the token from `path`, stdin or a prompt, in that order.
+// If `path` is "-" the token is read from stdin.
+func readToken(path string) string {
+	var (
+		token []byte
+		err   error
+	)
+	switch {
+	case path == "-":
+		token, err = ioutil.ReadAll(os.Stdin)
+	case path != "":
+		token, err = ioutil.ReadFile(path)
+	case !terminal.IsTerminal(int(os.Stdin.Fd())):
+		token, err = ioutil.ReadAll(os.Stdin)
+	default:
 		fmt.Println("Enter your UpBank token below and it will be inserted into your keyring for easy of use.")
 		fmt.Print("UpBank token: ")
-		token, err := terminal.ReadPassword(int(os.Stdin.Fd()))
-		if err != nil {
-			abort("Error: failed to read password from stdin: %v", err)
-		}
-
-		if err := keyring.SetTokenDefaultconfig(string(token)); err != nil {
-			abort("Error: failed to insert UpBank token into keyring: %v", err)
-		}
-	},
+		token, err = terminal.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+	}
+	if err != nil {
+		abort("Error: failed to read token: %v", err)
+	}
+
+	trimmed := strings.TrimSpace(string(token))
+	if trimmed == "" {
+		abort("Error: token is empty")
+	}
+	return trimmed
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// initCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// initCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	initCmd.Flags().StringVar(&initTokenFile, "token-file", "", "Read the token from this file, or stdin if it's '-'")
 }
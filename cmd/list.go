@@ -9,6 +9,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/store"
+)
+
+var (
+	listOffline bool
+	listAccount string
 )
 
 // listCmd represents the list command
@@ -21,14 +27,28 @@ var listAccountsCmd = &cobra.Command{
 	Use:   "accounts",
 	Short: "List accounts",
 	Run: func(cmd *cobra.Command, args []string) {
-		token := getToken()
-		client := upngo.NewClient(token)
-		accounts, err := client.Accounts()
-		if err != nil {
-			abort("Failed to get upbank accounts: %v", err)
+		var accounts []upngo.AccountResource
+		if listOffline {
+			s := openStore()
+			defer s.Close()
+
+			var err error
+			accounts, err = s.Accounts()
+			if err != nil {
+				abort("Failed to get upbank accounts: %v", err)
+			}
+		} else {
+			token := getToken()
+			client := newClient(token)
+			accountsResponse, err := client.Accounts.List()
+			if err != nil {
+				abort("Failed to get upbank accounts: %v", err)
+			}
+			accounts = accountsResponse.Data
 		}
+
 		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		for _, account := range accounts.Data {
+		for _, account := range accounts {
 			id := account.ID
 			name := account.Attributes.DisplayName
 			typ := account.Attributes.AccountType
@@ -43,36 +63,116 @@ var listTransactionsCmd = &cobra.Command{
 	Use:   "transactions",
 	Short: "List transactions",
 	Run: func(cmd *cobra.Command, args []string) {
-		token := getToken()
-		client := upngo.NewClient(token)
-		transactions, err := client.Transactions()
-		if err != nil {
-			abort("Failed to get upbank transactions: %v", err)
+		accountID := stringSetting(cmd, "account", configKeyAccount)
+		var transactions []upngo.TransactionResource
+		if listOffline {
+			s := openStore()
+			defer s.Close()
+
+			var err error
+			transactions, err = s.Transactions(store.TransactionFilter{AccountID: accountID})
+			if err != nil {
+				abort("Failed to get upbank transactions: %v", err)
+			}
+		} else {
+			token := getToken()
+			client := newClient(token)
+			var (
+				transactionsResponse upngo.TransactionsResponse
+				err                  error
+			)
+			if accountID == "" {
+				transactionsResponse, err = client.Transactions.List()
+			} else {
+				transactionsResponse, err = client.Accounts.Transactions(accountID)
+			}
+			if err != nil {
+				abort("Failed to get upbank transactions: %v", err)
+			}
+			transactions = transactionsResponse.Data
+		}
+
+		printTransactions(transactions)
+	},
+}
+
+var listCategoriesCmd = &cobra.Command{
+	Use:   "categories",
+	Short: "List categories",
+	Run: func(cmd *cobra.Command, args []string) {
+		var categories []upngo.CategoryResource
+		if listOffline {
+			s := openStore()
+			defer s.Close()
+
+			var err error
+			categories, err = s.Categories()
+			if err != nil {
+				abort("Failed to get upbank categories: %v", err)
+			}
+		} else {
+			token := getToken()
+			client := newClient(token)
+			categoriesResponse, err := client.Categories.List()
+			if err != nil {
+				abort("Failed to get upbank categories: %v", err)
+			}
+			categories = categoriesResponse.Data
 		}
 
 		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		for _, transaction := range transactions.Data {
-			id := transaction.ID
-			desc := transaction.Attributes.Description
-			msg := transaction.Attributes.Message
-			if msg == "" {
-				msg = "N/A"
+		for _, category := range categories {
+			parent := "N/A"
+			if category.Relationships.Parent.Data != nil {
+				parent = category.Relationships.Parent.Data.ID
 			}
-			amount := transaction.Attributes.Amount.Format()
-			date := transaction.Attributes.CreatedAt.Format(time.RFC1123)
-			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", desc, msg, amount, date, id)
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", category.Attributes.Name, parent, category.ID)
 		}
 		writer.Flush()
 	},
 }
 
+var listTagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List tags",
+	Run: func(cmd *cobra.Command, args []string) {
+		var tags []upngo.TagResource
+		if listOffline {
+			s := openStore()
+			defer s.Close()
+
+			var err error
+			tags, err = s.Tags()
+			if err != nil {
+				abort("Failed to get upbank tags: %v", err)
+			}
+		} else {
+			token := getToken()
+			client := newClient(token)
+			tagsResponse, err := client.Tags.List()
+			if err != nil {
+				abort("Failed to get upbank tags: %v", err)
+			}
+			tags = tagsResponse.Data
+		}
+
+		for _, tag := range tags {
+			fmt.Println(tag.ID)
+		}
+	},
+}
+
 var listWebhooksCmd = &cobra.Command{
 	Use:   "webhooks",
 	Short: "List webhooks",
 	Run: func(cmd *cobra.Command, args []string) {
+		if listOffline {
+			abort("Webhooks aren't kept in the local store so can't be listed offline")
+		}
+
 		token := getToken()
-		client := upngo.NewClient(token)
-		webhooks, err := client.Webhooks()
+		client := newClient(token)
+		webhooks, err := client.Webhooks.List()
 		if err != nil {
 			abort("Failed to get upbank webhooks: %v", err)
 		}
@@ -95,5 +195,8 @@ var listWebhooksCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(listCmd)
-	listCmd.AddCommand(listAccountsCmd, listTransactionsCmd, listWebhooksCmd)
+	listCmd.AddCommand(listAccountsCmd, listTransactionsCmd, listCategoriesCmd, listTagsCmd, listWebhooksCmd)
+
+	listTransactionsCmd.Flags().StringVarP(&listAccount, "account", "a", "", "Only list transactions from the account with this ID (default is the account setting)")
+	listCmd.PersistentFlags().BoolVar(&listOffline, "offline", false, "Use the local store (see upngo sync) instead of the API")
 }
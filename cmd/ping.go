@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/nick96/upngo"
 	"github.com/spf13/cobra"
 )
 
@@ -13,7 +12,7 @@ var pingCmd = &cobra.Command{
 	Short: "Ping UpBank. Useful to test your token is correct.",
 	Run: func(cmd *cobra.Command, args []string) {
 		token := getToken()
-		client := upngo.NewClient(token)
+		client := newClient(token)
 		if err := client.Ping(); err != nil {
 			abort("UpBank ping failed: %v", err)
 		}
@@ -27,9 +26,9 @@ var pingWebhookCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		token := getToken()
-		client := upngo.NewClient(token)
+		client := newClient(token)
 		id := args[0]
-		if _, err := client.PingWebhook(id); err != nil {
+		if _, err := client.Webhooks.Ping(id); err != nil {
 			abort("Webhook ping failed: %v", err)
 		}
 		fmt.Printf("Successfully pinged webhook ⚡\n")
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nick96/upngo/keyring"
+	"github.com/nick96/upngo/store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// profileEnvVar is the environment variable that selects the profile if the
+// --profile flag isn't given.
+const profileEnvVar = "UPNGO_PROFILE"
+
+var profileFlag string
+
+// currentProfile works out which profile to use. In order of precedence, it's
+// the --profile flag, the UPNGO_PROFILE env var, the profile chosen with
+// `upngo profile use` and finally the default profile.
+func currentProfile() string {
+	profile := profileFlag
+	if profile == "" {
+		profile = os.Getenv(profileEnvVar)
+	}
+	if profile == "" {
+		profile = viper.GetString(configKeyProfile)
+	}
+	if profile == "" {
+		profile = keyring.DefaultProfile
+	}
+
+	// Viper lowercases keys so profile names are case-insensitive.
+	profile = strings.ToLower(profile)
+	if err := validateProfile(profile); err != nil {
+		abort("Invalid profile: %v", err)
+	}
+	return profile
+}
+
+func validateProfile(profile string) error {
+	// An empty name would get the default profile's keyring key.
+	if strings.TrimSpace(profile) == "" {
+		return fmt.Errorf("profile names can't be empty")
+	}
+	// Profile names are used in config keys, keyring keys and the file names
+	// of their stores.
+	if strings.ContainsAny(profile, `.:/\`) {
+		return fmt.Errorf(`profile names can't contain '.', ':', '/' or '\'`)
+	}
+	// Its store would be the default profile's.
+	if profile+".db" == store.DefaultName {
+		return fmt.Errorf("%s is reserved", profile)
+	}
+	return nil
+}
+
+// profileKey is the config key `key` lives under for the current profile. The
+// default profile's config is at the top level so config written before
+// profiles existed still applies to it. Every other profile has its own
+// section under `profiles`.
+func profileKey(key string) string {
+	profile := currentProfile()
+	if profile == keyring.DefaultProfile {
+		return key
+	}
+	return "profiles." + profile + "." + key
+}
+
+// storePath is where the local store for the current profile lives.
+func storePath() string {
+	return storePathFor(currentProfile())
+}
+
+// storePathFor is where the local store for `profile` lives.
+func storePathFor(profile string) string {
+	path, err := store.DefaultPath()
+	if err != nil {
+		abort("Failed to find local store: %v", err)
+	}
+
+	if profile == keyring.DefaultProfile {
+		return path
+	}
+	return filepath.Join(filepath.Dir(path), profile+".db")
+}
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage profiles for using more than one UpBank token.",
+	Long: `Manage profiles for using more than one UpBank token.
+
+Each profile has its own token, config and local store. Create one with
+'upngo init --profile NAME' then select it with --profile, the UPNGO_PROFILE
+environment variable or 'upngo profile use NAME'.`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles. The current profile is marked with a '*'.",
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, err := keyring.Profiles()
+		if err != nil {
+			abort("Failed to list profiles: %v", err)
+		}
+
+		current := currentProfile()
+		for _, profile := range profiles {
+			marker := " "
+			if profile == current {
+				marker = "*"
+			}
+			fmt.Printf("%s %s\n", marker, profile)
+		}
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use [NAME]",
+	Short: "Use the given profile unless another is chosen with --profile or UPNGO_PROFILE.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := strings.ToLower(args[0])
+		if err := validateProfile(profile); err != nil {
+			abort("Invalid profile: %v", err)
+		}
+		if _, err := keyring.GetToken(profile); err != nil {
+			abort("Failed to find profile %s, create it with 'upngo init --profile %s': %v", profile, profile, err)
+		}
+
+		setConfig(configKeyProfile, profile)
+		fmt.Printf("Using profile %s\n", profile)
+	},
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:   "remove [NAME]",
+	Short: "Remove a profile's token, config and local store.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := strings.ToLower(args[0])
+		if err := validateProfile(profile); err != nil {
+			abort("Invalid profile: %v", err)
+		}
+		if profile == keyring.DefaultProfile {
+			abort("The default profile can't be removed")
+		}
+
+		if err := keyring.RemoveToken(profile); err != nil {
+			abort("Failed to remove token for profile %s: %v", profile, err)
+		}
+
+		keys := []string{"profiles." + profile}
+		// Only the file's setting matters, not an override from the
+		// environment.
+		if fileConfig().GetString(configKeyProfile) == profile {
+			keys = append(keys, configKeyProfile)
+		}
+		deleteConfigKeys(keys...)
+
+		// Otherwise it would still be counted by upngo balance networth.
+		if err := os.Remove(storePathFor(profile)); err != nil && !os.IsNotExist(err) {
+			abort("Failed to remove local store for profile %s: %v", profile, err)
+		}
+		fmt.Printf("Removed profile %s\n", profile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd, profileUseCmd, profileRemoveCmd)
+}
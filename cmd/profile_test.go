@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProfile(t *testing.T) {
+	require.NoError(t, validateProfile("work"))
+	for _, profile := range []string{"", " ", "../work", `a\b`, "a.b", "a:b", "upngo"} {
+		require.Error(t, validateProfile(profile), profile)
+	}
+}
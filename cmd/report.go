@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/chart"
+	"github.com/nick96/upngo/report"
+	"github.com/nick96/upngo/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportBy               string
+	reportSince            string
+	reportUntil            string
+	reportFormat           string
+	reportExcludeTransfers bool
+	reportExcludeRoundUps  bool
+	reportOffline          bool
+	reportChart            bool
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarise transactions.",
+}
+
+var reportSpendingCmd = &cobra.Command{
+	Use:   "spending",
+	Short: "Report income and expenses by category, merchant, account or period.",
+	Run: func(cmd *cobra.Command, args []string) {
+		since, until := parseDate(reportSince), parseDate(reportUntil)
+		options := report.Options{
+			By:               report.GroupBy(reportBy),
+			ExcludeTransfers: reportExcludeTransfers,
+			ExcludeRoundUps:  reportExcludeRoundUps,
+			AccountNames:     make(map[string]string),
+			CategoryNames:    make(map[string]string),
+		}
+
+		var (
+			accounts     []upngo.AccountResource
+			categories   []upngo.CategoryResource
+			transactions []upngo.TransactionResource
+			err          error
+		)
+		if reportOffline {
+			s := openStore()
+			defer s.Close()
+
+			if accounts, err = s.Accounts(); err != nil {
+				abort("Failed to get upbank accounts: %v", err)
+			}
+			if categories, err = s.Categories(); err != nil {
+				abort("Failed to get upbank categories: %v", err)
+			}
+			transactions, err = s.Transactions(store.TransactionFilter{Since: since, Until: until})
+			if err != nil {
+				abort("Failed to get upbank transactions: %v", err)
+			}
+		} else {
+			token := getToken()
+			client := newClient(token)
+			accounts = fetchAccounts(client)
+			categoriesResponse, err := client.Categories.List()
+			if err != nil {
+				abort("Failed to get upbank categories: %v", err)
+			}
+			categories = categoriesResponse.Data
+			transactions = fetchTransactions(client, "", transactionFilters(since, until)...)
+		}
+
+		for _, account := range accounts {
+			options.AccountNames[account.ID] = account.Attributes.DisplayName
+		}
+		for _, category := range categories {
+			options.CategoryNames[category.ID] = category.Attributes.Name
+		}
+
+		spending, err := report.NewSpending(transactions, options)
+		if err != nil {
+			abort("Failed to build spending report: %v", err)
+		}
+		if reportChart {
+			chartSpending(spending)
+			return
+		}
+		if err := spending.Write(os.Stdout, report.Format(stringSetting(cmd, "format", configKeyFormat))); err != nil {
+			abort("Failed to write spending report: %v", err)
+		}
+	},
+}
+
+// chartSpending draws the net amount of each row of the report as a bar chart,
+// followed by the total.
+func chartSpending(spending report.Spending) {
+	var points []chart.Point
+	for _, row := range spending.Rows {
+		points = append(points, chart.Point{Label: row.Label, Value: row.Net()})
+	}
+	options := chartOptions(spending.Currency)
+	if err := chart.Bars(os.Stdout, points, options); err != nil {
+		abort("Failed to draw spending chart: %v", err)
+	}
+	fmt.Printf("\n%s: %s\n", spending.Total.Label, options.Format(spending.Total.Net()))
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportSpendingCmd)
+
+	var groupBys []string
+	for _, by := range report.GroupBys {
+		groupBys = append(groupBys, string(by))
+	}
+
+	flags := reportSpendingCmd.Flags()
+	flags.StringVar(&reportBy, "by", string(report.GroupByCategory), fmt.Sprintf("What to group by (%s)", strings.Join(groupBys, "|")))
+	flags.StringVar(&reportSince, "since", "", "Only include transactions on or after this date (YYYY-MM-DD)")
+	flags.StringVar(&reportUntil, "until", "", "Only include transactions before this date (YYYY-MM-DD)")
+	flags.StringVarP(&reportFormat, "format", "f", string(report.FormatTable), "Output format (table|json|csv) (default is the format setting)")
+	flags.BoolVar(&reportExcludeTransfers, "exclude-transfers", false, "Leave out transfers between accounts")
+	flags.BoolVar(&reportExcludeRoundUps, "exclude-round-ups", false, "Leave out round-ups")
+	flags.BoolVar(&reportOffline, "offline", false, "Use the local store (see upngo sync) instead of the API")
+	flags.BoolVar(&reportChart, "chart", false, "Draw the net amount of each group as a bar chart instead of a table")
+}
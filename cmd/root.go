@@ -2,14 +2,18 @@ package cmd
 
 import (
 	"fmt"
-	"io/ioutil"
-	"log"
 	"os"
+	"strings"
 
+	"github.com/nick96/upngo/keyring"
 	"github.com/spf13/cobra"
 )
 
-var verbose *bool
+var (
+	verbose            int
+	keyringBackendFlag string
+	noCache            bool
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -27,10 +31,9 @@ func Execute() {
 }
 
 func init() {
-	verbose = rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Verbose logging")
-	cobra.OnInitialize(func() {
-		if !*verbose {
-			log.SetOutput(ioutil.Discard)
-		}
-	})
+	rootCmd.PersistentFlags().CountVarP(&verbose, "verbose", "v", "Verbose logging, give it twice (-vv) to include request and response bodies")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Profile to use, including its token even if $UPBANK_TOKEN is set (default is $UPNGO_PROFILE, then the profile chosen with 'upngo profile use')")
+	rootCmd.PersistentFlags().StringVar(&keyringBackendFlag, "keyring-backend", "", fmt.Sprintf("Keyring backend to store tokens in (%s) (default is the keyring-backend setting)", strings.Join(keyring.Backends, "|")))
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Don't use or update the cache of accounts, categories, tags and webhooks")
+	cobra.OnInitialize(initConfig, configureKeyring)
 }
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nick96/upngo/rules"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rulesFile   string
+	rulesSince  string
+	rulesUntil  string
+	rulesDryRun bool
+)
+
+// rulesCmd represents the rules command
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Automatically tag and categorise transactions.",
+	Long: `Automatically tag and categorise transactions.
+
+Rules live in a YAML file (by default rules.yaml next to the config file), e.g.
+
+  rules:
+    - name: uber
+      match:
+        description: ^uber
+        min-amount: 20
+      tags: [Transport]
+      category: taxis-and-share-cars
+
+A rule matches on description and rawtext regexes, min-amount and max-amount,
+account and foreign-currency. Every condition given has to match.`,
+}
+
+var rulesApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply the rules to existing transactions.",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := rulesFile
+		if path == "" {
+			path = filepath.Join(filepath.Dir(configPath()), "rules.yaml")
+		}
+		engine, err := rules.Load(path)
+		if err != nil {
+			abort("Failed to load rules: %v", err)
+		}
+
+		token := getToken()
+		client := newClient(token)
+		options := transactionFilters(parseDate(rulesSince), parseDate(rulesUntil))
+		transactions := fetchTransactions(client, "", options...)
+
+		failed := false
+		for _, transaction := range transactions {
+			change := engine.Plan(transaction)
+			if change.Empty() {
+				continue
+			}
+
+			var changes []string
+			if change.Category != "" {
+				changes = append(changes, "category "+change.Category)
+			}
+			if len(change.Tags) > 0 {
+				changes = append(changes, "tags "+strings.Join(change.Tags, ", "))
+			}
+			fmt.Printf(
+				"%s (%s): %s [%s]\n",
+				transaction.Attributes.Description,
+				transaction.ID,
+				strings.Join(changes, "; "),
+				strings.Join(change.Rules, ", "),
+			)
+
+			if rulesDryRun {
+				continue
+			}
+			if err := rules.Apply(client.Transactions, change); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to apply rules to %s: %v\n", transaction.ID, err)
+				failed = true
+			}
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesApplyCmd)
+
+	rulesCmd.PersistentFlags().StringVarP(
+		&rulesFile,
+		"rules",
+		"r",
+		"",
+		"Rules file (default is rules.yaml next to the config file)",
+	)
+	rulesApplyCmd.Flags().StringVar(&rulesSince, "since", "", "Only apply to transactions on or after this date (YYYY-MM-DD)")
+	rulesApplyCmd.Flags().StringVar(&rulesUntil, "until", "", "Only apply to transactions before this date (YYYY-MM-DD)")
+	rulesApplyCmd.Flags().BoolVarP(&rulesDryRun, "dry-run", "n", false, "Show what would change without changing anything")
+}
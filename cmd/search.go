@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/query"
+	"github.com/nick96/upngo/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchOffline bool
+	searchSince   string
+	searchUntil   string
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search [QUERY]",
+	Short: "Search transactions.",
+	Long: `Search transactions.
+
+Words in the query are searched for in the description, raw text and message of
+each transaction. Terms can also search specific fields, e.g.
+
+  upngo search 'uber amount>20 date:last-quarter'
+  upngo search 'description~/^(uber|didi)/ -status:held'
+  upngo search '(category:takeaway OR tag:Holiday) foreign:USD'
+
+The fields are description, rawtext, message, text, amount, direction, date,
+status, account, category, tag, currency and foreign. Terms are ANDed together
+unless separated by OR, and can be negated with NOT or a leading -.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Relative dates like last-week start at midnight in the configured
+		// timezone.
+		q, err := query.ParseAt(args[0], time.Now().In(location()))
+		if err != nil {
+			abort("Invalid query: %v", err)
+		}
+
+		since, until := parseDate(searchSince), parseDate(searchUntil)
+		var (
+			accounts     []upngo.AccountResource
+			transactions []upngo.TransactionResource
+		)
+		if searchOffline {
+			s := openStore()
+			defer s.Close()
+
+			accounts, err = s.Accounts()
+			if err != nil {
+				abort("Failed to get upbank accounts: %v", err)
+			}
+			transactions, err = s.Transactions(store.TransactionFilter{Since: since, Until: until})
+			if err != nil {
+				abort("Failed to get upbank transactions: %v", err)
+			}
+		} else {
+			token := getToken()
+			client := newClient(token)
+			accounts = fetchAccounts(client)
+			transactions = fetchTransactions(client, "", transactionFilters(since, until)...)
+		}
+
+		printTransactions(query.NewEvaluator(accounts).Filter(q, transactions))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().BoolVar(&searchOffline, "offline", false, "Search the local store (see upngo sync) instead of the API")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "Only search transactions on or after this date (YYYY-MM-DD)")
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "Only search transactions before this date (YYYY-MM-DD)")
+}
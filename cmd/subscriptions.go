@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/analysis"
+	"github.com/nick96/upngo/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	subscriptionsSince   string
+	subscriptionsAll     bool
+	subscriptionsOffline bool
+)
+
+// subscriptionsCmd represents the subscriptions command
+var subscriptionsCmd = &cobra.Command{
+	Use:   "subscriptions",
+	Short: "Find recurring payments and subscriptions.",
+	Long: `Find recurring payments and subscriptions.
+
+Looks through transaction history for merchants that charge at regular
+intervals (weekly, fortnightly, monthly or yearly) with stable amounts. Each is
+shown with when the next charge is expected and what it costs per year. Price
+changes between the last two charges are flagged.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		now := time.Now()
+		since := parseDate(subscriptionsSince)
+		if since.IsZero() {
+			// Long enough to catch yearly subscriptions.
+			since = now.AddDate(-1, -1, 0)
+		}
+
+		var transactions []upngo.TransactionResource
+		if subscriptionsOffline {
+			s := openStore()
+			defer s.Close()
+
+			var err error
+			transactions, err = s.Transactions(store.TransactionFilter{Since: since})
+			if err != nil {
+				abort("Failed to get upbank transactions: %v", err)
+			}
+		} else {
+			token := getToken()
+			client := newClient(token)
+			transactions = fetchTransactions(client, "", transactionFilters(since, time.Time{})...)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintf(writer, "Merchant\tFrequency\tAmount\tPer year\tLast charge\tNext charge\tNotes\n")
+		for _, subscription := range analysis.DetectSubscriptions(transactions, analysis.Options{}) {
+			var notes []string
+			if subscription.Lapsed(now) {
+				if !subscriptionsAll {
+					continue
+				}
+				notes = append(notes, "lapsed")
+			}
+			if subscription.PriceChanged() {
+				notes = append(notes, fmt.Sprintf(
+					"price changed from %s",
+					upngo.FormatBaseUnits(subscription.PreviousAmount, subscription.Currency),
+				))
+			}
+
+			fmt.Fprintf(
+				writer,
+				"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				subscription.Merchant,
+				subscription.Frequency,
+				upngo.FormatBaseUnits(subscription.Amount, subscription.Currency),
+				upngo.FormatBaseUnits(subscription.AnnualCost, subscription.Currency),
+				subscription.LastCharge.In(location()).Format(dateLayout()),
+				subscription.NextCharge.In(location()).Format(dateLayout()),
+				strings.Join(notes, ", "),
+			)
+		}
+		writer.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(subscriptionsCmd)
+
+	subscriptionsCmd.Flags().StringVar(&subscriptionsSince, "since", "", "Look at transactions on or after this date (YYYY-MM-DD) (default 13 months ago)")
+	subscriptionsCmd.Flags().BoolVar(&subscriptionsAll, "all", false, "Include subscriptions that look like they've been cancelled")
+	subscriptionsCmd.Flags().BoolVar(&subscriptionsOffline, "offline", false, "Use the local store (see upngo sync) instead of the API")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nick96/upngo/store"
+	"github.com/spf13/cobra"
+)
+
+var syncFull bool
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync accounts, transactions, categories and tags to the local store.",
+	Long: `Sync accounts, transactions, categories and tags to the local store.
+
+Only transactions since the last sync are fetched, so after the first sync this
+is quick. Once synced, the list commands can be run with --offline to use the
+local copy instead of the API.
+
+Each account's daily balances are recorded too, for upngo balance. The cache is
+never used so the balances are always current.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		token := getToken()
+		client := newUncachedClient(token)
+		s := openStore()
+		defer s.Close()
+
+		result, err := store.Sync(client, s, syncFull)
+		if err != nil {
+			abort("Failed to sync: %v", err)
+		}
+		if err := s.SnapshotBalances(result.Since, time.Now(), location()); err != nil {
+			abort("Failed to record balances: %v", err)
+		}
+
+		fmt.Printf(
+			"Synced %d accounts, %d categories and %d tags\n",
+			result.Accounts,
+			result.Categories,
+			result.Tags,
+		)
+		fmt.Printf(
+			"Transactions: %d added, %d updated, %d deleted\n",
+			result.Added,
+			result.Updated,
+			result.Deleted,
+		)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolVar(&syncFull, "full", false, "Fetch all transactions rather than just those since the last sync")
+}
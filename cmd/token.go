@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nick96/upngo/keyring"
+	"github.com/spf13/cobra"
+)
+
+var tokenRotateFile string
+
+// tokenCmd represents the token command
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Check and manage your UpBank token.",
+}
+
+var tokenStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show where the token comes from and whether it works.",
+	Run: func(cmd *cobra.Command, args []string) {
+		token, source, err := findToken()
+		if err != nil {
+			abort("Failed to find UpBank token: %v", err)
+		}
+
+		fmt.Printf("Profile: %s\n", currentProfile())
+		fmt.Printf("Source:  %s\n", source)
+		response, err := newClient(token).PingWithResponse()
+		if err != nil {
+			fmt.Printf("Status:  not working (%v)\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Status:  working %s\n", response.Meta.StatusEmoji)
+		fmt.Printf("ID:      %s\n", response.Meta.ID)
+	},
+}
+
+var tokenRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Replace the token in the keyring with a new one.",
+	Long: `Replace the token in the keyring with a new one.
+
+The new token is checked before it replaces the old one. Like init, it's
+prompted for if stdin is a terminal and otherwise read from stdin or
+--token-file. Remember to revoke the old token in the Up app.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		token := readToken(tokenRotateFile)
+		saveToken(token)
+	},
+}
+
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the token for the current profile from the keyring.",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := currentProfile()
+		if err := keyring.RemoveToken(profile); err != nil {
+			abort("Failed to remove UpBank token for profile %s from keyring: %v", profile, err)
+		}
+		fmt.Printf("Removed token for profile %s\n", profile)
+
+		if _, ok := os.LookupEnv(tokenEnvVar); ok {
+			fmt.Printf("Note: %s is still set and will be used instead of the keyring\n", tokenEnvVar)
+		}
+	},
+}
+
+// saveToken checks the token works and then saves it to the keyring for the
+// current profile.
+func saveToken(token string) {
+	response, err := newClient(token).PingWithResponse()
+	if err != nil {
+		abort("Error: token didn't work, so it hasn't been saved: %v", err)
+	}
+
+	profile := currentProfile()
+	if err := keyring.SetToken(profile, token); err != nil {
+		abort("Error: failed to insert UpBank token into keyring: %v", err)
+	}
+	fmt.Printf("Saved token %s for profile %s %s\n", response.Meta.ID, profile, response.Meta.StatusEmoji)
+}
+
+func init() {
+	rootCmd.AddCommand(tokenCmd, logoutCmd)
+	tokenCmd.AddCommand(tokenStatusCmd, tokenRotateCmd)
+
+	tokenRotateCmd.Flags().StringVar(&tokenRotateFile, "token-file", "", "Read the token from this file, or stdin if it's '-'")
+}
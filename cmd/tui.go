@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nick96/upngo/tui"
+)
+
+var tuiAccount string
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse accounts and transactions interactively.",
+	Long: `Browse accounts and transactions interactively.
+
+The accounts and their balances are on the left and the transactions in the
+chosen account are on the right, with the details of the selected one below.
+More transactions are loaded as you scroll down.
+
+Keys:
+  tab    switch between accounts and transactions
+  enter  show the transactions in the selected account
+  t      tag the selected transaction
+  c      categorize the selected transaction
+  f      filter transactions by date and status
+  r      reload transactions
+  q      quit
+
+Logs from -v are written to stderr, which is also the screen, so redirect them
+to a file, e.g. upngo tui -v 2>upngo.log.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		token := getToken()
+		client := newClient(token)
+		err := tui.Run(client, tui.Options{
+			AccountID:  stringSetting(cmd, "account", configKeyAccount),
+			PageSize:   pageSize(),
+			Location:   location(),
+			DateLayout: dateLayout(),
+		})
+		if err != nil {
+			abort("Failed to run TUI: %v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+
+	tuiCmd.Flags().StringVarP(&tuiAccount, "account", "a", "", "Show the transactions in the account with this ID first (default is the account setting, or all accounts)")
+}
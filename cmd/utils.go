@@ -1,11 +1,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"log"
 	"os"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/cache"
 	"github.com/nick96/upngo/keyring"
+	"github.com/nick96/upngo/store"
+)
+
+const (
+	// maxPageSize is the largest page size the API allows. Using it means we
+	// make fewer requests when we need to get everything.
+	maxPageSize = 100
 )
 
 func abort(msg string, args ...interface{}) {
@@ -16,31 +30,191 @@ func abort(msg string, args ...interface{}) {
 	os.Exit(1)
 }
 
-func getToken() string {
-	// The token can either be in an env var or a keyring. If someone has
-	// set the env var they probably intend to do that over the keychain
-	// because it's useful for testing and stuff.
-	var token string
-	if value, ok := os.LookupEnv("UPBANK_TOKEN"); ok {
-		token = value
-	} else {
-		// Not sure if it's just me being dumb but it looks like we can't
-		// assign `token` (an existing variable) and define `err` in a
-		// single statement. So, instead, we just assign a temporary
-		// `keyringToken` and set token to that value once we know there
-		// were no issues getting it out of the keyring.
-		keyringToken, err := keyring.GetTokenDefaultConfig()
-		if err != nil {
-			abort("Failed to get UpBank token from keyring: %v", err)
-		}
+const (
+	tokenEnvVar     = "UPBANK_TOKEN"
+	tokenFileEnvVar = "UPBANK_TOKEN_FILE"
+)
 
-		token = keyringToken
+func getToken() string {
+	token, _, err := findToken()
+	if err != nil {
+		abort("Failed to find UpBank token: %v", err)
 	}
 
 	// We won't be able to use the API without a token so just blow up here.
 	if token == "" {
-		abort("Failed to find UpBank token in UPBANK_TOKEN environment variable and keyring")
+		abort("Failed to find UpBank token in %s or %s environment variables and keyring", tokenEnvVar, tokenFileEnvVar)
 	}
 
 	return token
 }
+
+// findToken finds the token and describes where it came from.
+func findToken() (string, string, error) {
+	// A profile given with --profile always uses its own token, otherwise it
+	// would be silently ignored whenever the env vars are set.
+	if profileFlag == "" {
+		token, source, ok, err := envToken()
+		if ok {
+			if profile := currentProfile(); profile != keyring.DefaultProfile {
+				fmt.Fprintf(os.Stderr, "Warning: using the token from %s instead of profile %s's token\n", source, profile)
+			}
+			return token, source, err
+		}
+	}
+
+	profile := currentProfile()
+	token, err := keyring.GetToken(profile)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get UpBank token for profile %s from keyring: %w", profile, err)
+	}
+	return token, fmt.Sprintf("keyring (profile %s)", profile), nil
+}
+
+// envToken gets the token from the environment and describes where it came
+// from. It returns false if the token isn't in the environment.
+func envToken() (string, string, bool, error) {
+	// The token can either be in an env var, a file named by an env var or
+	// a keyring. If someone has set the env var they probably intend to do
+	// that over the keychain because it's useful for testing and stuff.
+	if value, ok := os.LookupEnv(tokenEnvVar); ok {
+		return value, tokenEnvVar + " environment variable", true, nil
+	}
+
+	if path, ok := os.LookupEnv(tokenFileEnvVar); ok {
+		source := fmt.Sprintf("file %s (from %s)", path, tokenFileEnvVar)
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", source, true, fmt.Errorf("failed to read UpBank token from %s: %w", path, err)
+		}
+		return strings.TrimSpace(string(contents)), source, true, nil
+	}
+	return "", "", false, nil
+}
+
+// newClient creates a client that logs to stderr with as much detail as was
+// asked for with -v and caches reference data unless --no-cache was given.
+func newClient(token string) *upngo.Client {
+	options := clientOptions()
+	if !noCache {
+		options = append(options, openCache().Option())
+	}
+	return upngo.NewClient(token, options...)
+}
+
+// newUncachedClient creates a client like newClient but without the cache, for
+// commands that treat what the API says, like account balances, as the truth.
+func newUncachedClient(token string) *upngo.Client {
+	return upngo.NewClient(token, clientOptions()...)
+}
+
+// clientOptions are the options every client is created with.
+func clientOptions() []upngo.ClientOption {
+	level := upngo.LogLevel(verbose)
+	if level > upngo.LogBodies {
+		level = upngo.LogBodies
+	}
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	return []upngo.ClientOption{upngo.WithLogger(logger, level)}
+}
+
+// openCache opens the cache in its default directory.
+func openCache() *cache.Cache {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		abort("Failed to find cache directory: %v", err)
+	}
+	return cache.New(dir, nil)
+}
+
+// parseDate parses a date given on the command line. An empty value is allowed
+// and results in the zero time.
+func parseDate(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	date, err := time.ParseInLocation(dateLayout(), value, location())
+	if err != nil {
+		abort("Invalid date %q, expected the format %s", value, dateLayout())
+	}
+	return date
+}
+
+// transactionFilters builds the options to filter transactions to the period
+// between `since` and `until`. Either can be zero to leave that end open.
+func transactionFilters(since, until time.Time) []upngo.TransactionsOption {
+	options := []upngo.TransactionsOption{upngo.WithPageSize(pageSize())}
+	if !since.IsZero() {
+		options = append(options, upngo.WithFilterSince(since))
+	}
+	if !until.IsZero() {
+		options = append(options, upngo.WithFilterUntil(until))
+	}
+	return options
+}
+
+// fetchAccounts gets every page of accounts.
+func fetchAccounts(client *upngo.Client) []upngo.AccountResource {
+	page, err := client.Accounts.List()
+	var accounts []upngo.AccountResource
+	for err == nil {
+		accounts = append(accounts, page.Data...)
+		page, err = client.Accounts.Next(page)
+	}
+	if !errors.Is(err, upngo.ErrNoNextPage) {
+		abort("Failed to get upbank accounts: %v", err)
+	}
+	return accounts
+}
+
+// fetchTransactions gets every page of transactions, either for all accounts or
+// just the account with ID `accountID` if it isn't empty.
+func fetchTransactions(client *upngo.Client, accountID string, options ...upngo.TransactionsOption) []upngo.TransactionResource {
+	var (
+		page upngo.TransactionsResponse
+		err  error
+	)
+	if accountID == "" {
+		page, err = client.Transactions.List(options...)
+	} else {
+		page, err = client.Accounts.Transactions(accountID, options...)
+	}
+
+	var transactions []upngo.TransactionResource
+	for err == nil {
+		transactions = append(transactions, page.Data...)
+		page, err = client.Transactions.Next(page)
+	}
+	if !errors.Is(err, upngo.ErrNoNextPage) {
+		abort("Failed to get upbank transactions: %v", err)
+	}
+
+	return transactions
+}
+
+// openStore opens the local store for the current profile.
+func openStore() *store.Store {
+	s, err := store.Open(storePath())
+	if err != nil {
+		abort("Failed to open local store: %v", err)
+	}
+	return s
+}
+
+// printTransactions prints the transactions as a table.
+func printTransactions(transactions []upngo.TransactionResource) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	for _, transaction := range transactions {
+		id := transaction.ID
+		desc := transaction.Attributes.Description
+		msg := transaction.Attributes.Message
+		if msg == "" {
+			msg = "N/A"
+		}
+		amount := transaction.Attributes.Amount.Format()
+		date := transaction.Attributes.CreatedAt.In(location()).Format(time.RFC1123)
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", desc, msg, amount, date, id)
+	}
+	writer.Flush()
+}
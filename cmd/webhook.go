@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nick96/upngo/keyring"
+	"github.com/spf13/cobra"
+)
+
+// webhookCmd represents the webhook command
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Manage registered webhooks.",
+}
+
+var webhookSecretCmd = &cobra.Command{
+	Use:   "secret [ID]",
+	Short: "Print the secret key of a webhook saved with 'upngo add webhook --save-secret'.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		secretKey, err := keyring.GetWebhookSecret(args[0])
+		if err != nil {
+			abort("Failed to get secret key of webhook %s: %v", args[0], err)
+		}
+		fmt.Println(secretKey)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(webhookCmd)
+	webhookCmd.AddCommand(webhookSecretCmd)
+}
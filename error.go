@@ -6,6 +6,15 @@ import (
 
 var ErrNotImplemented = errors.New("not implemented")
 
+// ErrNoImplementation is returned by a sub-client, like AccountsClient, that
+// wasn't created with its constructor, like NewAccountsClient, so there's
+// nothing for it to send requests with.
+var ErrNoImplementation = errors.New("sub-client has no implementation")
+
+// ErrNoNextPage is returned when trying to get the next page of a paginated
+// response that is already on the last page.
+var ErrNoNextPage = errors.New("no next page")
+
 type ErrorObject struct {
 	Status string `json:"status"`
 	Title  string `json:"title"`
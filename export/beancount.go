@@ -0,0 +1,57 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/nick96/upngo"
+)
+
+// WriteBeancount writes the transactions to `w` as beancount entries. The
+// UpBank transaction ID is included as `up-id` metadata on each entry to make
+// duplicates easy to spot on re-import.
+//
+// Only transactions are written, not `open` directives, so the accounts need to
+// be opened elsewhere in the ledger.
+func WriteBeancount(
+	w io.Writer,
+	accounts []upngo.AccountResource,
+	transactions []upngo.TransactionResource,
+	mapping Mapping,
+) error {
+	entries, err := buildJournal(accounts, transactions, mapping)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(w)
+	for i, entry := range entries {
+		if i > 0 {
+			fmt.Fprint(writer, "\n")
+		}
+
+		flag := "*"
+		if entry.pending {
+			flag = "!"
+		}
+		fmt.Fprintf(
+			writer,
+			"%s %s %s %s\n",
+			entry.date.Format("2006-01-02"),
+			flag,
+			strconv.Quote(entry.payee),
+			strconv.Quote(entry.narration),
+		)
+		fmt.Fprintf(writer, "  up-id: %s\n", strconv.Quote(entry.id))
+		for _, posting := range entry.postings {
+			fmt.Fprintf(writer, "  %s  %s %s\n", posting.account, posting.amount, posting.currency)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write beancount journal: %w", err)
+	}
+	return nil
+}
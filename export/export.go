@@ -0,0 +1,61 @@
+// Package export converts UpBank transactions into formats understood by
+// personal finance tools, such as GnuCash, that don't speak the UpBank API.
+package export
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+// accountTransactions is an account along with all the transactions that
+// belong to it.
+type accountTransactions struct {
+	account      upngo.AccountResource
+	transactions []upngo.TransactionResource
+}
+
+// groupByAccount groups the transactions by the account they belong to. The
+// groups are in the same order as `accounts` and the transactions in each group
+// are sorted by the date they were posted. Every account gets a group, even if
+// it has no transactions, but it is an error for a transaction to belong to an
+// account that isn't in `accounts`.
+func groupByAccount(
+	accounts []upngo.AccountResource,
+	transactions []upngo.TransactionResource,
+) ([]accountTransactions, error) {
+	groups := make([]accountTransactions, len(accounts))
+	indexes := make(map[string]int, len(accounts))
+	for i, account := range accounts {
+		groups[i].account = account
+		indexes[account.ID] = i
+	}
+
+	for _, transaction := range transactions {
+		accountID := transaction.Relationships.Account.Data.ID
+		i, ok := indexes[accountID]
+		if !ok {
+			return nil, fmt.Errorf("transaction %s belongs to unknown account %s", transaction.ID, accountID)
+		}
+		groups[i].transactions = append(groups[i].transactions, transaction)
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group.transactions, func(i, j int) bool {
+			return postedAt(group.transactions[i]).Before(postedAt(group.transactions[j]))
+		})
+	}
+
+	return groups, nil
+}
+
+// postedAt is the date a transaction should be recorded against. Held
+// transactions haven't settled yet so we fall back to when they were created.
+func postedAt(transaction upngo.TransactionResource) time.Time {
+	if transaction.Attributes.SettledAt.IsZero() {
+		return transaction.Attributes.CreatedAt
+	}
+	return transaction.Attributes.SettledAt
+}
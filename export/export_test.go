@@ -0,0 +1,180 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+func newAccount(id, name string, typ upngo.AccountType, balance string) upngo.AccountResource {
+	return upngo.AccountResource{
+		ID:   id,
+		Type: "accounts",
+		Attributes: upngo.AttributesObject{
+			DisplayName: name,
+			AccountType: typ,
+			Balance: upngo.MoneyObject{
+				CurrencyCode: "AUD",
+				Value:        balance,
+			},
+		},
+	}
+}
+
+func newTransaction(id, accountID, description, message, amount string, settledAt time.Time) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.ID = id
+	transaction.Attributes.Description = description
+	transaction.Attributes.Message = message
+	transaction.Attributes.Amount = upngo.MoneyObject{CurrencyCode: "AUD", Value: amount}
+	if strings.HasPrefix(amount, "-") {
+		transaction.Attributes.Amount.ValueInBaseUnits = -1
+	}
+	transaction.Attributes.CreatedAt = settledAt
+	transaction.Attributes.SettledAt = settledAt
+	transaction.Relationships.Account.Data.ID = accountID
+	return transaction
+}
+
+func TestWriteQIF(t *testing.T) {
+	accounts := []upngo.AccountResource{
+		newAccount("spending", "Spending", upngo.AccountTypeTransactional, "10.00"),
+		newAccount("saver", "Savings", upngo.AccountTypeSaver, "100.00"),
+	}
+	transactions := []upngo.TransactionResource{
+		newTransaction("2", "spending", "Coffee", "", "-4.50", time.Date(2020, 8, 3, 9, 0, 0, 0, time.UTC)),
+		newTransaction("1", "spending", "Pay", "thanks boss", "1000.00", time.Date(2020, 8, 2, 9, 0, 0, 0, time.UTC)),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteQIF(&buf, accounts, transactions))
+
+	expected := `!Account
+NSpending
+TBank
+^
+!Type:Bank
+D08/02/2020
+T1000.00
+PPay
+Mthanks boss
+^
+D08/03/2020
+T-4.50
+PCoffee
+^
+!Account
+NSavings
+TBank
+^
+!Type:Bank
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteOFX(t *testing.T) {
+	accounts := []upngo.AccountResource{
+		newAccount("spending", "Spending", upngo.AccountTypeTransactional, "10.00"),
+	}
+	transactions := []upngo.TransactionResource{
+		newTransaction(
+			"a0b1",
+			"spending",
+			"A really long description that needs truncating",
+			"",
+			"-4.50",
+			time.Date(2020, 8, 3, 9, 0, 0, 0, time.UTC),
+		),
+	}
+	now := time.Date(2020, 8, 4, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeOFX(&buf, accounts, transactions, now))
+
+	ofx := buf.String()
+	require.True(t, strings.HasPrefix(ofx, ofxHeader))
+	require.Contains(t, ofx, "<ACCTID>spending</ACCTID>")
+	require.Contains(t, ofx, "<ACCTTYPE>CHECKING</ACCTTYPE>")
+	require.Contains(t, ofx, "<TRNTYPE>DEBIT</TRNTYPE>")
+	require.Contains(t, ofx, "<DTPOSTED>20200803090000.000[0:GMT]</DTPOSTED>")
+	require.Contains(t, ofx, "<TRNAMT>-4.50</TRNAMT>")
+	require.Contains(t, ofx, "<FITID>a0b1</FITID>")
+	require.Contains(t, ofx, "<NAME>A really long description that n</NAME>")
+	require.NotContains(t, ofx, "<MEMO>")
+	require.Contains(t, ofx, "<BALAMT>10.00</BALAMT>")
+	require.Contains(t, ofx, "<DTASOF>20200804000000.000[0:GMT]</DTASOF>")
+}
+
+func TestUnknownAccount(t *testing.T) {
+	transactions := []upngo.TransactionResource{
+		newTransaction("1", "missing", "Coffee", "", "-4.50", time.Now()),
+	}
+	require.Error(t, WriteQIF(&bytes.Buffer{}, nil, transactions))
+	require.Error(t, WriteOFX(&bytes.Buffer{}, nil, transactions))
+}
+
+func TestWriteLedger(t *testing.T) {
+	accounts := []upngo.AccountResource{
+		newAccount("spending", "⚡ Spending", upngo.AccountTypeTransactional, "10.00"),
+		newAccount("saver", "🏖 Holiday fund", upngo.AccountTypeSaver, "100.00"),
+	}
+
+	coffee := newTransaction("1", "spending", "Coffee", "", "-4.50", time.Date(2020, 8, 2, 9, 0, 0, 0, time.UTC))
+	coffee.Relationships.Category.Data = &upngo.DataObject{Type: "categories", ID: "restaurants-and-cafes"}
+	coffee.Attributes.RoundUp.Amount = upngo.MoneyObject{CurrencyCode: "AUD", Value: "-0.50", ValueInBaseUnits: -50}
+
+	transferOut := newTransaction("2", "spending", "Transfer to Holiday fund", "", "-20.00", time.Date(2020, 8, 3, 9, 0, 0, 0, time.UTC))
+	transferOut.Relationships.TransferAccount.Data = &upngo.DataObject{Type: "accounts", ID: "saver"}
+	transferIn := newTransaction("3", "saver", "Transfer from Spending", "", "20.00", time.Date(2020, 8, 3, 9, 0, 0, 0, time.UTC))
+	transferIn.Relationships.TransferAccount.Data = &upngo.DataObject{Type: "accounts", ID: "spending"}
+	transferIn.Attributes.Amount.ValueInBaseUnits = 2000
+
+	mapping := Mapping{
+		Accounts:   map[string]string{"spending": "Assets:Bank:Up"},
+		Categories: map[string]string{"restaurants-and-cafes": "Expenses:Food:Coffee"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteLedger(&buf, accounts, []upngo.TransactionResource{transferIn, coffee, transferOut}, mapping))
+
+	expected := `2020/08/02 * Coffee
+    ; up-id: 1
+    Expenses:Food:Coffee  4.50 AUD
+    Assets:Bank:Up  -4.50 AUD
+
+2020/08/02 * Round Up
+    ; up-id: 1-round-up
+    ; Coffee
+    Assets:Up:HolidayFund  0.50 AUD
+    Assets:Bank:Up  -0.50 AUD
+
+2020/08/03 * Transfer to Holiday fund
+    ; up-id: 2
+    Assets:Up:HolidayFund  20.00 AUD
+    Assets:Bank:Up  -20.00 AUD
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteBeancount(t *testing.T) {
+	accounts := []upngo.AccountResource{
+		newAccount("spending", "Spending", upngo.AccountTypeTransactional, "10.00"),
+	}
+	pay := newTransaction("1", "spending", "Pay", `"thanks" boss`, "1000.00", time.Date(2020, 8, 2, 9, 0, 0, 0, time.UTC))
+	pay.Attributes.Amount.ValueInBaseUnits = 100000
+	pay.Attributes.Status = upngo.TransactionStatusHeld
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteBeancount(&buf, accounts, []upngo.TransactionResource{pay}, Mapping{}))
+
+	expected := `2020-08-02 ! "Pay" "\"thanks\" boss"
+  up-id: "1"
+  Income:Uncategorised  -1000.00 AUD
+  Assets:Up:Spending  1000.00 AUD
+`
+	require.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,232 @@
+package export
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nick96/upngo"
+)
+
+const (
+	defaultAssetsPrefix   = "Assets:Up:"
+	defaultExpenseAccount = "Expenses:Uncategorised"
+	defaultIncomeAccount  = "Income:Uncategorised"
+)
+
+// Mapping describes how UpBank accounts and categories map to the accounts in
+// a plain-text accounting journal. Anything that isn't mapped gets a sensible
+// default so an empty mapping still produces a valid journal.
+type Mapping struct {
+	// Accounts maps UpBank account IDs to journal account names. Unmapped
+	// accounts are named after their display name under `Assets:Up`.
+	Accounts map[string]string `mapstructure:"accounts"`
+	// Categories maps UpBank category IDs to journal account names. A
+	// transaction's category is looked up first, then its parent category.
+	Categories map[string]string `mapstructure:"categories"`
+	// Expenses is the account for spending that has no mapped category.
+	Expenses string `mapstructure:"expenses"`
+	// Income is the account for money coming in that has no mapped
+	// category.
+	Income string `mapstructure:"income"`
+	// RoundUps is the account round-ups are transferred to. If it isn't set
+	// then the first saver account is used.
+	RoundUps string `mapstructure:"round-ups"`
+}
+
+// posting is a single leg of a journal entry.
+type posting struct {
+	account  string
+	amount   string
+	currency string
+}
+
+// journalEntry is a balanced journal entry. It is agnostic to the journal
+// format so each writer only has to worry about how it's laid out.
+type journalEntry struct {
+	date      time.Time
+	pending   bool
+	payee     string
+	narration string
+	// id is unique to the entry so it can be used to deduplicate entries
+	// when the same period is imported twice.
+	id       string
+	postings []posting
+}
+
+// journal turns UpBank accounts and transactions into journal entries.
+type journal struct {
+	mapping  Mapping
+	accounts map[string]upngo.AccountResource
+}
+
+func newJournal(accounts []upngo.AccountResource, mapping Mapping) journal {
+	j := journal{
+		mapping:  mapping,
+		accounts: make(map[string]upngo.AccountResource, len(accounts)),
+	}
+	for _, account := range accounts {
+		j.accounts[account.ID] = account
+		if j.mapping.RoundUps == "" && account.Attributes.AccountType == upngo.AccountTypeSaver {
+			j.mapping.RoundUps = j.accountName(account.ID)
+		}
+	}
+	if j.mapping.Expenses == "" {
+		j.mapping.Expenses = defaultExpenseAccount
+	}
+	if j.mapping.Income == "" {
+		j.mapping.Income = defaultIncomeAccount
+	}
+	if j.mapping.RoundUps == "" {
+		j.mapping.RoundUps = defaultAssetsPrefix + "RoundUps"
+	}
+	return j
+}
+
+// accountName gets the journal account name for the UpBank account with the
+// given ID.
+func (j journal) accountName(id string) string {
+	if name, ok := j.mapping.Accounts[id]; ok {
+		return name
+	}
+	if account, ok := j.accounts[id]; ok {
+		if name := sanitiseAccountName(account.Attributes.DisplayName); name != "" {
+			return defaultAssetsPrefix + name
+		}
+	}
+	return defaultAssetsPrefix + sanitiseAccountName(id)
+}
+
+// categoryName gets the journal account name for the transaction's category.
+func (j journal) categoryName(transaction upngo.TransactionResource) string {
+	relationships := transaction.Relationships
+	for _, category := range []upngo.OptionalRelationshipObject{relationships.Category, relationships.ParentCategory} {
+		if category.Data == nil {
+			continue
+		}
+		if name, ok := j.mapping.Categories[category.Data.ID]; ok {
+			return name
+		}
+	}
+
+	if transaction.Attributes.Amount.ValueInBaseUnits < 0 {
+		return j.mapping.Expenses
+	}
+	return j.mapping.Income
+}
+
+// entries builds the journal entries for the transactions, sorted by date.
+func (j journal) entries(transactions []upngo.TransactionResource) []journalEntry {
+	var entries []journalEntry
+	for _, transaction := range transactions {
+		attributes := transaction.Attributes
+		accountID := transaction.Relationships.Account.Data.ID
+		entry := journalEntry{
+			date:      postedAt(transaction),
+			pending:   attributes.Status == upngo.TransactionStatusHeld,
+			payee:     attributes.Description,
+			narration: attributes.Message,
+			id:        transaction.ID,
+		}
+
+		counterAccount := j.categoryName(transaction)
+		if transfer := transaction.Relationships.TransferAccount.Data; transfer != nil {
+			// Both sides of a transfer between our own accounts show up
+			// as transactions. Only the outgoing side is recorded so the
+			// transfer isn't counted twice.
+			if _, ok := j.accounts[transfer.ID]; ok && attributes.Amount.ValueInBaseUnits > 0 {
+				continue
+			}
+			counterAccount = j.accountName(transfer.ID)
+		}
+
+		entry.postings = []posting{
+			{
+				account:  counterAccount,
+				amount:   negate(attributes.Amount.Value),
+				currency: attributes.Amount.CurrencyCode,
+			},
+			{
+				account:  j.accountName(accountID),
+				amount:   attributes.Amount.Value,
+				currency: attributes.Amount.CurrencyCode,
+			},
+		}
+		entries = append(entries, entry)
+
+		if roundUp := attributes.RoundUp.Amount; roundUp.ValueInBaseUnits != 0 {
+			entries = append(entries, journalEntry{
+				date:      entry.date,
+				pending:   entry.pending,
+				payee:     "Round Up",
+				narration: attributes.Description,
+				id:        transaction.ID + "-round-up",
+				postings: []posting{
+					{
+						account:  j.mapping.RoundUps,
+						amount:   negate(roundUp.Value),
+						currency: roundUp.CurrencyCode,
+					},
+					{
+						account:  j.accountName(accountID),
+						amount:   roundUp.Value,
+						currency: roundUp.CurrencyCode,
+					},
+				},
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, k int) bool {
+		return entries[i].date.Before(entries[k].date)
+	})
+	return entries
+}
+
+// buildJournal validates the transactions belong to the accounts and builds the
+// journal entries for them.
+func buildJournal(
+	accounts []upngo.AccountResource,
+	transactions []upngo.TransactionResource,
+	mapping Mapping,
+) ([]journalEntry, error) {
+	if _, err := groupByAccount(accounts, transactions); err != nil {
+		return nil, err
+	}
+	return newJournal(accounts, mapping).entries(transactions), nil
+}
+
+// negate negates a decimal amount without going through a float so the value
+// stays exact.
+func negate(value string) string {
+	if strings.HasPrefix(value, "-") {
+		return value[1:]
+	}
+	if strings.Trim(value, "0.") == "" {
+		return value
+	}
+	return "-" + value
+}
+
+// sanitiseAccountName converts an UpBank display name into something that is
+// valid as a component of both ledger and beancount account names. Emoji and
+// punctuation are dropped and each word is capitalised, e.g. "🏖 Holiday fund"
+// becomes "HolidayFund".
+func sanitiseAccountName(name string) string {
+	var builder strings.Builder
+	for _, word := range strings.Fields(name) {
+		first := true
+		for _, r := range word {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				continue
+			}
+			if first {
+				r = unicode.ToUpper(r)
+				first = false
+			}
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
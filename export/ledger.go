@@ -0,0 +1,51 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/nick96/upngo"
+)
+
+// WriteLedger writes the transactions to `w` as ledger-cli journal entries,
+// which hledger can also read. The UpBank transaction ID is included as
+// `up-id` metadata on each entry to make duplicates easy to spot on re-import.
+func WriteLedger(
+	w io.Writer,
+	accounts []upngo.AccountResource,
+	transactions []upngo.TransactionResource,
+	mapping Mapping,
+) error {
+	entries, err := buildJournal(accounts, transactions, mapping)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(w)
+	for i, entry := range entries {
+		if i > 0 {
+			fmt.Fprint(writer, "\n")
+		}
+
+		status := "*"
+		if entry.pending {
+			status = "!"
+		}
+		fmt.Fprintf(writer, "%s %s %s\n", entry.date.Format("2006/01/02"), status, entry.payee)
+		fmt.Fprintf(writer, "    ; up-id: %s\n", entry.id)
+		if entry.narration != "" {
+			fmt.Fprintf(writer, "    ; %s\n", entry.narration)
+		}
+		for _, posting := range entry.postings {
+			// Ledger needs at least two spaces between the account and
+			// the amount because account names can contain spaces.
+			fmt.Fprintf(writer, "    %s  %s %s\n", posting.account, posting.amount, posting.currency)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write ledger journal: %w", err)
+	}
+	return nil
+}
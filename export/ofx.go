@@ -0,0 +1,195 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+const (
+	// ofxHeader is the processing instructions that mark the document as
+	// being OFX 2.2.
+	ofxHeader = xml.Header + `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+	// ofxBankID is UpBank's BSB. All Up accounts share it.
+	ofxBankID = "633123"
+	// ofxMaxNameLength is the maximum length of the NAME element of a
+	// transaction.
+	ofxMaxNameLength = 32
+)
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+var ofxStatusOK = ofxStatus{Code: 0, Severity: "INFO"}
+
+type ofxSignOn struct {
+	Status     ofxStatus `xml:"STATUS"`
+	ServerDate string    `xml:"DTSERVER"`
+	Language   string    `xml:"LANGUAGE"`
+}
+
+type ofxBankAccount struct {
+	BankID      string `xml:"BANKID"`
+	AccountID   string `xml:"ACCTID"`
+	AccountType string `xml:"ACCTTYPE"`
+}
+
+type ofxTransaction struct {
+	Type   string `xml:"TRNTYPE"`
+	Posted string `xml:"DTPOSTED"`
+	Amount string `xml:"TRNAMT"`
+	FITID  string `xml:"FITID"`
+	Name   string `xml:"NAME"`
+	Memo   string `xml:"MEMO,omitempty"`
+}
+
+type ofxTransactionList struct {
+	Start        string           `xml:"DTSTART"`
+	End          string           `xml:"DTEND"`
+	Transactions []ofxTransaction `xml:"STMTTRN"`
+}
+
+type ofxBalance struct {
+	Amount string `xml:"BALAMT"`
+	AsOf   string `xml:"DTASOF"`
+}
+
+type ofxStatement struct {
+	Currency        string             `xml:"CURDEF"`
+	Account         ofxBankAccount     `xml:"BANKACCTFROM"`
+	TransactionList ofxTransactionList `xml:"BANKTRANLIST"`
+	LedgerBalance   ofxBalance         `xml:"LEDGERBAL"`
+}
+
+type ofxStatementResponse struct {
+	TransactionUID string       `xml:"TRNUID"`
+	Status         ofxStatus    `xml:"STATUS"`
+	Statement      ofxStatement `xml:"STMTRS"`
+}
+
+type ofxDocument struct {
+	XMLName    xml.Name               `xml:"OFX"`
+	SignOn     ofxSignOn              `xml:"SIGNONMSGSRSV1>SONRS"`
+	Statements []ofxStatementResponse `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+// formatOFXDate formats the time in the OFX datetime format. Everything is
+// converted to UTC so we don't have to worry about mapping to timezone names.
+func formatOFXDate(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+// ofxAccountType maps the UpBank account type to the closest OFX account type.
+func ofxAccountType(accountType upngo.AccountType) string {
+	switch accountType {
+	case upngo.AccountTypeSaver:
+		return "SAVINGS"
+	case upngo.AccountTypeTransactional:
+		return "CHECKING"
+	default:
+		return "CHECKING"
+	}
+}
+
+func newOFXTransaction(transaction upngo.TransactionResource) ofxTransaction {
+	typ := "CREDIT"
+	if transaction.Attributes.Amount.ValueInBaseUnits < 0 {
+		typ = "DEBIT"
+	}
+
+	name := []rune(transaction.Attributes.Description)
+	if len(name) > ofxMaxNameLength {
+		name = name[:ofxMaxNameLength]
+	}
+
+	return ofxTransaction{
+		Type:   typ,
+		Posted: formatOFXDate(postedAt(transaction)),
+		Amount: transaction.Attributes.Amount.Value,
+		// The transaction ID is unique and stable so it's exactly what the
+		// FITID is for. Importers use it to avoid duplicating transactions
+		// when the same period is exported more than once.
+		FITID: transaction.ID,
+		Name:  string(name),
+		Memo:  transaction.Attributes.Message,
+	}
+}
+
+func newOFXStatement(group accountTransactions, now time.Time) ofxStatementResponse {
+	list := ofxTransactionList{
+		Start: formatOFXDate(now),
+		End:   formatOFXDate(now),
+	}
+	if len(group.transactions) > 0 {
+		list.Start = formatOFXDate(postedAt(group.transactions[0]))
+		list.End = formatOFXDate(postedAt(group.transactions[len(group.transactions)-1]))
+	}
+	for _, transaction := range group.transactions {
+		list.Transactions = append(list.Transactions, newOFXTransaction(transaction))
+	}
+
+	balance := group.account.Attributes.Balance
+	return ofxStatementResponse{
+		TransactionUID: group.account.ID,
+		Status:         ofxStatusOK,
+		Statement: ofxStatement{
+			Currency: balance.CurrencyCode,
+			Account: ofxBankAccount{
+				BankID:      ofxBankID,
+				AccountID:   group.account.ID,
+				AccountType: ofxAccountType(group.account.Attributes.AccountType),
+			},
+			TransactionList: list,
+			LedgerBalance: ofxBalance{
+				Amount: balance.Value,
+				AsOf:   formatOFXDate(now),
+			},
+		},
+	}
+}
+
+// WriteOFX writes the transactions to `w` as an OFX 2.2 document. There is one
+// statement for each account, with the ledger balance being the account's
+// current balance.
+func WriteOFX(w io.Writer, accounts []upngo.AccountResource, transactions []upngo.TransactionResource) error {
+	return writeOFX(w, accounts, transactions, time.Now())
+}
+
+func writeOFX(
+	w io.Writer,
+	accounts []upngo.AccountResource,
+	transactions []upngo.TransactionResource,
+	now time.Time,
+) error {
+	groups, err := groupByAccount(accounts, transactions)
+	if err != nil {
+		return err
+	}
+
+	document := ofxDocument{
+		SignOn: ofxSignOn{
+			Status:     ofxStatusOK,
+			ServerDate: formatOFXDate(now),
+			Language:   "ENG",
+		},
+	}
+	for _, group := range groups {
+		document.Statements = append(document.Statements, newOFXStatement(group, now))
+	}
+
+	if _, err := io.WriteString(w, ofxHeader); err != nil {
+		return fmt.Errorf("failed to write OFX header: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to encode OFX document: %w", err)
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
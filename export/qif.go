@@ -0,0 +1,42 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/nick96/upngo"
+)
+
+// qifDateFormat is the US style date format that QIF importers expect.
+const qifDateFormat = "01/02/2006"
+
+// WriteQIF writes the transactions to `w` in the Quicken Interchange Format.
+// Each account gets its own `!Account` block so importers can put the
+// transactions in the right place.
+func WriteQIF(w io.Writer, accounts []upngo.AccountResource, transactions []upngo.TransactionResource) error {
+	groups, err := groupByAccount(accounts, transactions)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(w)
+	for _, group := range groups {
+		fmt.Fprintf(writer, "!Account\nN%s\nTBank\n^\n", group.account.Attributes.DisplayName)
+		fmt.Fprint(writer, "!Type:Bank\n")
+		for _, transaction := range group.transactions {
+			fmt.Fprintf(writer, "D%s\n", postedAt(transaction).Format(qifDateFormat))
+			fmt.Fprintf(writer, "T%s\n", transaction.Attributes.Amount.Value)
+			fmt.Fprintf(writer, "P%s\n", transaction.Attributes.Description)
+			if transaction.Attributes.Message != "" {
+				fmt.Fprintf(writer, "M%s\n", transaction.Attributes.Message)
+			}
+			fmt.Fprint(writer, "^\n")
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write QIF: %w", err)
+	}
+	return nil
+}
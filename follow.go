@@ -0,0 +1,88 @@
+package upngo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrNoLink is returned when trying to follow a link that isn't there, e.g.
+// the category of an uncategorised transaction.
+var ErrNoLink = errors.New("no link to follow")
+
+// ErrForeignLink is returned when trying to follow a link that isn't to the
+// API the client talks to. The client won't follow it because it would send
+// the token somewhere else.
+var ErrForeignLink = errors.New("link isn't to the UpBank API")
+
+// Follow gets the resource at `link`, which is one of the `self` or `related`
+// links in a response, and unmarshals it into `out`. `out` should be a pointer
+// to the response type for the resource, e.g. *TransactionsResponse for an
+// account's related transactions link.
+func (c *Client) Follow(link string, out interface{}) error {
+	if link == "" {
+		return ErrNoLink
+	}
+	if !strings.HasPrefix(link, c.baseURL+"/") {
+		return fmt.Errorf("%w: %s", ErrForeignLink, link)
+	}
+
+	return c.do(request{
+		name:   "follow link " + link,
+		method: http.MethodGet,
+		url:    link,
+	}, out)
+}
+
+// FetchTransactions gets the first page of the account's transactions.
+func (a AccountResource) FetchTransactions(c *Client) (TransactionsResponse, error) {
+	var transactions TransactionsResponse
+	err := c.Follow(a.Relationships.Transactions.Links.Related, &transactions)
+	return transactions, err
+}
+
+// FetchAccount gets the account the transaction belongs to.
+func (t TransactionResource) FetchAccount(c *Client) (AccountResponse, error) {
+	var account AccountResponse
+	err := c.Follow(t.Relationships.Account.Links.Related, &account)
+	return account, err
+}
+
+// FetchTransferAccount gets the account the money went to or came from if the
+// transaction is a transfer. If it isn't, ErrNoLink is returned.
+func (t TransactionResource) FetchTransferAccount(c *Client) (AccountResponse, error) {
+	var account AccountResponse
+	err := c.Follow(optionalLink(t.Relationships.TransferAccount), &account)
+	return account, err
+}
+
+// FetchCategory gets the transaction's category. If it's uncategorised,
+// ErrNoLink is returned.
+func (t TransactionResource) FetchCategory(c *Client) (CategoryResponse, error) {
+	var category CategoryResponse
+	err := c.Follow(optionalLink(t.Relationships.Category), &category)
+	return category, err
+}
+
+// FetchTransaction gets the transaction the event is about. Events that aren't
+// about a transaction, like pings, return ErrNoLink.
+func (e WebhookEventResource) FetchTransaction(c *Client) (TransactionResponse, error) {
+	var transaction TransactionResponse
+	err := c.Follow(e.Relationships.Transaction.Links.Related, &transaction)
+	return transaction, err
+}
+
+// FetchWebhook gets the webhook the event was sent to.
+func (e WebhookEventResource) FetchWebhook(c *Client) (WebhookResponse, error) {
+	var webhook WebhookResponse
+	err := c.Follow(e.Relationships.Webhook.Links.Related, &webhook)
+	return webhook, err
+}
+
+func optionalLink(relationship OptionalRelationshipObject) string {
+	if relationship.Data == nil || relationship.Links == nil {
+		return ""
+	}
+	return relationship.Links.Related
+}
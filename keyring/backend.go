@@ -0,0 +1,94 @@
+package keyring
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/99designs/keyring"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// PassphraseEnvVar is the environment variable the passphrase for the
+// encrypted file backend is read from. If it isn't set the passphrase is
+// prompted for on the terminal.
+const PassphraseEnvVar = "UPNGO_KEYRING_PASSPHRASE"
+
+// Backends are the names of the keyring backends that can be chosen.
+var Backends = []string{
+	string(keyring.SecretServiceBackend),
+	string(keyring.KWalletBackend),
+	string(keyring.PassBackend),
+	string(keyring.FileBackend),
+	string(keyring.KeychainBackend),
+	string(keyring.WinCredBackend),
+}
+
+// Options choose which keyring backend is used and how it's set up.
+type Options struct {
+	// Backend is the name of the backend to use. If it's empty the first
+	// available backend is used.
+	Backend string
+	// FileDir is the directory the encrypted file backend keeps its files
+	// in. It defaults to `$XDG_DATA_HOME/upngo/keyring`.
+	FileDir string
+	// PassDir is the password store used by the pass backend. It defaults to
+	// pass' own default.
+	PassDir string
+}
+
+// Configure updates `Config` to use the backend given in `opts`.
+func Configure(opts Options) error {
+	if opts.Backend == "" {
+		Config.AllowedBackends = nil
+	} else {
+		if !isBackend(opts.Backend) {
+			return fmt.Errorf("unknown keyring backend '%s', expected one of %s", opts.Backend, strings.Join(Backends, ", "))
+		}
+		Config.AllowedBackends = []keyring.BackendType{keyring.BackendType(opts.Backend)}
+	}
+
+	Config.FileDir = opts.FileDir
+	if Config.FileDir == "" {
+		Config.FileDir = defaultFileDir()
+	}
+	Config.FilePasswordFunc = filePassword
+	Config.PassDir = opts.PassDir
+
+	return nil
+}
+
+func isBackend(name string) bool {
+	for _, backend := range Backends {
+		if name == backend {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultFileDir() string {
+	// The file backend resolves `~` itself.
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join("~", ".local", "share")
+	}
+	return filepath.Join(dataHome, "upngo", "keyring")
+}
+
+func filePassword(prompt string) (string, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnvVar); ok {
+		return passphrase, nil
+	}
+
+	// Prompt on stderr so it doesn't end up in output that's being piped
+	// somewhere.
+	fmt.Fprintf(os.Stderr, "%s: ", prompt)
+	passphrase, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read keyring passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
@@ -2,12 +2,20 @@ package keyring
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/99designs/keyring"
 )
 
 const (
-	upbankTokenKey = "upbank-token"
+	upbankTokenKey   = "upbank-token"
+	webhookSecretKey = "webhook-secret"
+
+	// DefaultProfile is the profile used when no other is chosen. Its token
+	// is stored under the same key tokens were stored under before profiles
+	// existed so existing setups keep working.
+	DefaultProfile = "default"
 )
 
 var Config = keyring.Config{
@@ -16,35 +24,149 @@ var Config = keyring.Config{
 	// KeychainName: "upngo",
 }
 
+// tokenKey is the keyring key the token for `profile` is stored under.
+func tokenKey(profile string) string {
+	if profile == "" || profile == DefaultProfile {
+		return upbankTokenKey
+	}
+	return upbankTokenKey + ":" + profile
+}
+
+// profileFromKey is the inverse of `tokenKey`. It returns false if `key` isn't
+// a token key.
+func profileFromKey(key string) (string, bool) {
+	if key == upbankTokenKey {
+		return DefaultProfile, true
+	}
+	if strings.HasPrefix(key, upbankTokenKey+":") {
+		return strings.TrimPrefix(key, upbankTokenKey+":"), true
+	}
+	return "", false
+}
+
 // GetTokenDefaultConfig gets the upbank token using the default config
 // (`Config`).
 func GetTokenDefaultConfig() (string, error) {
+	return GetToken(DefaultProfile)
+}
+
+func SetTokenDefaultconfig(token string) error {
+	return SetToken(DefaultProfile, token)
+}
+
+// GetToken gets the upbank token for `profile` using the default config
+// (`Config`).
+func GetToken(profile string) (string, error) {
 	kr, err := keyring.Open(Config)
 	if err != nil {
 		return "", fmt.Errorf("failed to open keyring with default config: %w", err)
 	}
 
-	tokenItem, err := kr.Get(upbankTokenKey)
+	tokenItem, err := kr.Get(tokenKey(profile))
 	if err != nil {
-		return "", fmt.Errorf("failed to get token from keyring with default config: %w", err)
+		return "", fmt.Errorf("failed to get token for profile '%s' from keyring with default config: %w", profile, err)
 	}
 
 	return string(tokenItem.Data), nil
 }
 
-func SetTokenDefaultconfig(token string) error {
+// SetToken sets the upbank token for `profile` using the default config
+// (`Config`).
+func SetToken(profile, token string) error {
 	kr, err := keyring.Open(Config)
 	if err != nil {
 		return fmt.Errorf("failed to open keyring with default config: %w", err)
 	}
 
+	key := tokenKey(profile)
 	err = kr.Set(keyring.Item{
-		Key:  upbankTokenKey,
+		Key:  key,
 		Data: []byte(token),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to set key '%s' in keyring to UpBank token: %w", upbankTokenKey, err)
+		return fmt.Errorf("failed to set key '%s' in keyring to UpBank token: %w", key, err)
+	}
+
+	return nil
+}
+
+// RemoveToken removes the upbank token for `profile` from the keyring.
+func RemoveToken(profile string) error {
+	kr, err := keyring.Open(Config)
+	if err != nil {
+		return fmt.Errorf("failed to open keyring with default config: %w", err)
+	}
+
+	key := tokenKey(profile)
+	if err := kr.Remove(key); err != nil {
+		return fmt.Errorf("failed to remove key '%s' from keyring: %w", key, err)
+	}
+
+	return nil
+}
+
+// Profiles lists the profiles that have a token in the keyring, sorted by
+// name.
+func Profiles() ([]string, error) {
+	kr, err := keyring.Open(Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keyring with default config: %w", err)
+	}
+
+	keys, err := kr.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys in keyring: %w", err)
+	}
+
+	var profiles []string
+	for _, key := range keys {
+		if profile, ok := profileFromKey(key); ok {
+			profiles = append(profiles, profile)
+		}
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// GetWebhookSecret gets the secret key of the webhook with ID `webhookID`.
+func GetWebhookSecret(webhookID string) (string, error) {
+	kr, err := keyring.Open(Config)
+	if err != nil {
+		return "", fmt.Errorf("failed to open keyring with default config: %w", err)
+	}
+
+	secretItem, err := kr.Get(webhookSecretKey + ":" + webhookID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret key for webhook '%s' from keyring: %w", webhookID, err)
+	}
+
+	return string(secretItem.Data), nil
+}
+
+// SetWebhookSecret stores the secret key of the webhook with ID `webhookID`.
+func SetWebhookSecret(webhookID, secretKey string) error {
+	kr, err := keyring.Open(Config)
+	if err != nil {
+		return fmt.Errorf("failed to open keyring with default config: %w", err)
+	}
+
+	key := webhookSecretKey + ":" + webhookID
+	err = kr.Set(keyring.Item{
+		Key:  key,
+		Data: []byte(secretKey),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set key '%s' in keyring to webhook secret key: %w", key, err)
 	}
 
 	return nil
 }
+
+// WebhookSecrets looks up webhook secret keys stored with `SetWebhookSecret`.
+// It can be given to `webhook.NewReceiverWithSecrets`.
+type WebhookSecrets struct{}
+
+// WebhookSecret gets the secret key of the webhook with ID `webhookID`.
+func (WebhookSecrets) WebhookSecret(webhookID string) (string, error) {
+	return GetWebhookSecret(webhookID)
+}
@@ -0,0 +1,69 @@
+package keyring
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenKey(t *testing.T) {
+	require.Equal(t, "upbank-token", tokenKey(DefaultProfile))
+	require.Equal(t, "upbank-token", tokenKey(""))
+	require.Equal(t, "upbank-token:joint", tokenKey("joint"))
+
+	for _, profile := range []string{DefaultProfile, "joint"} {
+		got, ok := profileFromKey(tokenKey(profile))
+		require.True(t, ok)
+		require.Equal(t, profile, got)
+	}
+
+	_, ok := profileFromKey("webhook-secret")
+	require.False(t, ok)
+}
+
+func TestConfigureFileBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upngo-keyring")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	original := Config
+	defer func() { Config = original }()
+	os.Setenv(PassphraseEnvVar, "hunter2")
+	defer os.Unsetenv(PassphraseEnvVar)
+
+	require.NoError(t, Configure(Options{Backend: "file", FileDir: dir}))
+
+	require.NoError(t, SetToken(DefaultProfile, "personal-token"))
+	require.NoError(t, SetToken("joint", "joint-token"))
+
+	token, err := GetToken("joint")
+	require.NoError(t, err)
+	require.Equal(t, "joint-token", token)
+
+	profiles, err := Profiles()
+	require.NoError(t, err)
+	require.Equal(t, []string{DefaultProfile, "joint"}, profiles)
+
+	require.NoError(t, SetWebhookSecret("webhook-id", "webhook-secret"))
+	secret, err := WebhookSecrets{}.WebhookSecret("webhook-id")
+	require.NoError(t, err)
+	require.Equal(t, "webhook-secret", secret)
+
+	// Webhook secrets aren't profiles.
+	profiles, err = Profiles()
+	require.NoError(t, err)
+	require.Equal(t, []string{DefaultProfile, "joint"}, profiles)
+
+	require.NoError(t, RemoveToken("joint"))
+	_, err = GetToken("joint")
+	require.Error(t, err)
+}
+
+func TestConfigureUnknownBackend(t *testing.T) {
+	original := Config
+	defer func() { Config = original }()
+
+	require.Error(t, Configure(Options{Backend: "post-it-note"}))
+}
@@ -0,0 +1,66 @@
+package upngo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrRateLimited is returned when a request can't be made without going over
+// the client's rate limit, either because the context asked to fail fast or
+// because waiting would go past the context's deadline.
+var ErrRateLimited = errors.New("rate limited")
+
+// WithRateLimit limits the client to `perSecond` requests per second on
+// average, with bursts of up to `burst` requests. The limit is shared by every
+// goroutine using the client. Requests wait for their turn unless their
+// context is marked with FailFast, or the wait would go past the context's
+// deadline, in which case they fail with ErrRateLimited.
+func WithRateLimit(perSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.limiter = rate.NewLimiter(rate.Limit(perSecond), burst)
+	}
+}
+
+type failFastKey struct{}
+
+// FailFast marks `ctx` so that requests made with it fail with ErrRateLimited
+// rather than waiting when the client's rate limit has been reached.
+func FailFast(ctx context.Context) context.Context {
+	return context.WithValue(ctx, failFastKey{}, true)
+}
+
+func isFailFast(ctx context.Context) bool {
+	failFast, _ := ctx.Value(failFastKey{}).(bool)
+	return failFast
+}
+
+type limitTransport struct {
+	rt      http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if isFailFast(ctx) {
+		if !t.limiter.Allow() {
+			return nil, ErrRateLimited
+		}
+	} else if err := t.limiter.Wait(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %v", ErrRateLimited, err)
+	}
+	return t.rt.RoundTrip(req)
+}
+
+func newLimitTransport(rt http.RoundTripper, limiter *rate.Limiter) http.RoundTripper {
+	if limiter == nil {
+		return rt
+	}
+	return &limitTransport{rt, limiter}
+}
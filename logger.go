@@ -0,0 +1,74 @@
+package upngo
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Logger is where the client writes its logs. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// LogLevel is how much detail the client logs.
+type LogLevel int
+
+const (
+	// LogOff logs nothing. It's the default.
+	LogOff LogLevel = iota
+	// LogRequests logs the method and URL of each request and the status of
+	// each response.
+	LogRequests
+	// LogBodies logs headers and bodies as well as everything LogRequests
+	// does.
+	LogBodies
+)
+
+// redacted replaces sensitive values in logs.
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are headers that are never logged as is.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Up-Authenticity-Signature",
+}
+
+// sensitiveFields matches JSON fields whose values are never logged as is.
+var sensitiveFields = regexp.MustCompile(`("secretKey"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// WithLogger makes the client log to `logger` with the given level of detail.
+// Tokens, webhook secret keys and other sensitive values are always redacted.
+func WithLogger(logger Logger, level LogLevel) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+		c.logLevel = level
+	}
+}
+
+// redactHeaders returns a copy of `header` with sensitive values redacted.
+func redactHeaders(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range sensitiveHeaders {
+		if _, ok := header[http.CanonicalHeaderKey(name)]; ok {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
+
+// redactBody returns `body` with the values of sensitive JSON fields redacted.
+func redactBody(body []byte) string {
+	return sensitiveFields.ReplaceAllString(string(body), `$1"`+redacted+`"`)
+}
+
+// redactToken replaces any occurrence of `token` in `s`. It's the last line of
+// defence in case the token ends up somewhere we didn't expect.
+func redactToken(s, token string) string {
+	if token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, token, redacted)
+}
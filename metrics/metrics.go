@@ -0,0 +1,210 @@
+// Package metrics collects metrics about the requests a upngo.Client makes and
+// exposes them in the Prometheus text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nick96/upngo"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the request duration
+// histogram buckets if none are given.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// series identifies the requests to one endpoint with one method.
+type series struct {
+	method   string
+	endpoint string
+}
+
+func (s series) labels() string {
+	return fmt.Sprintf(`method="%s",endpoint="%s"`, escape(s.method), escape(s.endpoint))
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Collector is a upngo.Observer that counts requests by endpoint, method and
+// status code, records a histogram of their durations and counts retries and
+// errors. It's an http.Handler that serves the metrics in the Prometheus text
+// format so it can be scraped directly. It's safe for concurrent use.
+type Collector struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[series]map[int]uint64
+	durations map[series]*histogram
+	retries   map[series]uint64
+	errors    map[series]uint64
+}
+
+// NewCollector creates a collector with the given histogram buckets, or
+// DefaultBuckets if there aren't any.
+func NewCollector(buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Collector{
+		buckets:   buckets,
+		requests:  make(map[series]map[int]uint64),
+		durations: make(map[series]*histogram),
+		retries:   make(map[series]uint64),
+		errors:    make(map[series]uint64),
+	}
+}
+
+// ObserveRequest implements upngo.Observer.
+func (c *Collector) ObserveRequest(info upngo.RequestInfo) {
+	s := series{info.Method, info.Endpoint}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if info.Err != nil {
+		c.errors[s]++
+	} else {
+		if c.requests[s] == nil {
+			c.requests[s] = make(map[int]uint64)
+		}
+		c.requests[s][info.StatusCode]++
+	}
+	if info.Attempt > 1 {
+		c.retries[s]++
+	}
+
+	h := c.durations[s]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.durations[s] = h
+	}
+	seconds := info.Duration.Seconds()
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// WriteTo writes the metrics to `w` in the Prometheus text format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	fmt.Fprintln(cw, "# HELP upngo_requests_total Responses from the Up API by status code, including retries.")
+	fmt.Fprintln(cw, "# TYPE upngo_requests_total counter")
+	for _, s := range sortedSeries(c.requests) {
+		var codes []int
+		for code := range c.requests[s] {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			fmt.Fprintf(cw, "upngo_requests_total{%s,code=\"%d\"} %d\n", s.labels(), code, c.requests[s][code])
+		}
+	}
+
+	fmt.Fprintln(cw, "# HELP upngo_request_errors_total Requests to the Up API that failed without a response.")
+	fmt.Fprintln(cw, "# TYPE upngo_request_errors_total counter")
+	for _, s := range sortedSeries(c.errors) {
+		fmt.Fprintf(cw, "upngo_request_errors_total{%s} %d\n", s.labels(), c.errors[s])
+	}
+
+	fmt.Fprintln(cw, "# HELP upngo_request_retries_total Requests to the Up API that were retries.")
+	fmt.Fprintln(cw, "# TYPE upngo_request_retries_total counter")
+	for _, s := range sortedSeries(c.retries) {
+		fmt.Fprintf(cw, "upngo_request_retries_total{%s} %d\n", s.labels(), c.retries[s])
+	}
+
+	fmt.Fprintln(cw, "# HELP upngo_request_duration_seconds How long requests to the Up API took.")
+	fmt.Fprintln(cw, "# TYPE upngo_request_duration_seconds histogram")
+	for _, s := range sortedSeries(c.durations) {
+		h := c.durations[s]
+		for i, bound := range c.buckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			fmt.Fprintf(cw, "upngo_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", s.labels(), le, h.counts[i])
+		}
+		fmt.Fprintf(cw, "upngo_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", s.labels(), h.count)
+		fmt.Fprintf(cw, "upngo_request_duration_seconds_sum{%s} %s\n", s.labels(), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(cw, "upngo_request_duration_seconds_count{%s} %d\n", s.labels(), h.count)
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.Flush()
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if _, err := c.WriteTo(w); err != nil {
+		http.Error(w, "failed to write metrics", http.StatusInternalServerError)
+	}
+}
+
+// sortedSeries returns the keys of `m`, which must be a map keyed by series,
+// in a stable order.
+func sortedSeries(m interface{}) []series {
+	var keys []series
+	switch m := m.(type) {
+	case map[series]map[int]uint64:
+		for s := range m {
+			keys = append(keys, s)
+		}
+	case map[series]*histogram:
+		for s := range m {
+			keys = append(keys, s)
+		}
+	case map[series]uint64:
+		for s := range m {
+			keys = append(keys, s)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].method < keys[j].method
+	})
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector(0.1, 1)
+	collector.ObserveRequest(upngo.RequestInfo{Method: "GET", Endpoint: "/accounts", StatusCode: 200, Duration: 50 * time.Millisecond, Attempt: 1})
+	collector.ObserveRequest(upngo.RequestInfo{Method: "GET", Endpoint: "/accounts", StatusCode: 429, Duration: 500 * time.Millisecond, Attempt: 1})
+	collector.ObserveRequest(upngo.RequestInfo{Method: "GET", Endpoint: "/accounts", StatusCode: 200, Duration: 2 * time.Second, Attempt: 2})
+	collector.ObserveRequest(upngo.RequestInfo{Method: "GET", Endpoint: "/util/ping", Duration: time.Second, Attempt: 1, Err: errors.New("boom")})
+
+	var out bytes.Buffer
+	n, err := collector.WriteTo(&out)
+	require.NoError(t, err)
+	require.Equal(t, int64(out.Len()), n)
+	require.Equal(t, `# HELP upngo_requests_total Responses from the Up API by status code, including retries.
+# TYPE upngo_requests_total counter
+upngo_requests_total{method="GET",endpoint="/accounts",code="200"} 2
+upngo_requests_total{method="GET",endpoint="/accounts",code="429"} 1
+# HELP upngo_request_errors_total Requests to the Up API that failed without a response.
+# TYPE upngo_request_errors_total counter
+upngo_request_errors_total{method="GET",endpoint="/util/ping"} 1
+# HELP upngo_request_retries_total Requests to the Up API that were retries.
+# TYPE upngo_request_retries_total counter
+upngo_request_retries_total{method="GET",endpoint="/accounts"} 1
+# HELP upngo_request_duration_seconds How long requests to the Up API took.
+# TYPE upngo_request_duration_seconds histogram
+upngo_request_duration_seconds_bucket{method="GET",endpoint="/accounts",le="0.1"} 1
+upngo_request_duration_seconds_bucket{method="GET",endpoint="/accounts",le="1"} 2
+upngo_request_duration_seconds_bucket{method="GET",endpoint="/accounts",le="+Inf"} 3
+upngo_request_duration_seconds_sum{method="GET",endpoint="/accounts"} 2.55
+upngo_request_duration_seconds_count{method="GET",endpoint="/accounts"} 3
+upngo_request_duration_seconds_bucket{method="GET",endpoint="/util/ping",le="0.1"} 0
+upngo_request_duration_seconds_bucket{method="GET",endpoint="/util/ping",le="1"} 1
+upngo_request_duration_seconds_bucket{method="GET",endpoint="/util/ping",le="+Inf"} 1
+upngo_request_duration_seconds_sum{method="GET",endpoint="/util/ping"} 1
+upngo_request_duration_seconds_count{method="GET",endpoint="/util/ping"} 1
+`, out.String())
+}
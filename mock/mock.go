@@ -0,0 +1,190 @@
+// Package mock is an in-memory implementation of the UpBank API's services for
+// testing code that uses upngo without a server.
+//
+// A Bank holds the accounts, transactions, categories and webhooks and its
+// services read and change them like the API would:
+//
+//	bank := mock.New()
+//	bank.AddAccounts(account)
+//	bank.AddTransactions(transaction)
+//	client := bank.Client()
+//	// Use client.Transactions etc. as normal.
+//
+// Lists are always returned as a single page, so options like
+// upngo.WithPageSize are ignored. The transaction filters
+// upngo.WithFilterSince, upngo.WithFilterUntil and upngo.WithFilterStatus are
+// applied, and so is upngo.WithFilterParent for categories.
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+// ErrNotFound is returned when a resource doesn't exist in the bank.
+var ErrNotFound = errors.New("not found")
+
+// Bank is an in-memory UpBank. It's safe for concurrent use.
+type Bank struct {
+	Accounts     *Accounts
+	Transactions *Transactions
+	Categories   *Categories
+	Tags         *Tags
+	Webhooks     *Webhooks
+
+	mu           sync.Mutex
+	err          error
+	accounts     []upngo.AccountResource
+	transactions []upngo.TransactionResource
+	categories   []upngo.CategoryResource
+	webhooks     []upngo.WebhookResource
+	logs         map[string][]upngo.WebhookDeliveryLogResource
+	nextID       int
+	now          func() time.Time
+}
+
+// New creates an empty bank.
+func New() *Bank {
+	b := &Bank{
+		logs: make(map[string][]upngo.WebhookDeliveryLogResource),
+		now:  time.Now,
+	}
+	b.Accounts = &Accounts{bank: b}
+	b.Transactions = &Transactions{bank: b}
+	b.Categories = &Categories{bank: b}
+	b.Tags = &Tags{bank: b}
+	b.Webhooks = &Webhooks{bank: b}
+	return b
+}
+
+// Client creates a client whose services use the bank. Only the services are
+// mocked, anything else, such as Ping, still goes to the API.
+func (b *Bank) Client(options ...upngo.ClientOption) *upngo.Client {
+	client := upngo.NewClient("mock", options...)
+	client.Accounts = upngo.NewAccountsClient(b.Accounts)
+	client.Transactions = upngo.NewTransactionsClient(b.Transactions)
+	client.Categories = upngo.NewCategoriesClient(b.Categories)
+	client.Tags = upngo.NewTagsClient(b.Tags)
+	client.Webhooks = upngo.NewWebhooksClient(b.Webhooks)
+	return client
+}
+
+// FailWith makes every request fail with `err` until it's called again with
+// nil. It's for testing how errors from the API are handled.
+func (b *Bank) FailWith(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err = err
+}
+
+// AddAccounts adds accounts to the bank.
+func (b *Bank) AddAccounts(accounts ...upngo.AccountResource) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.accounts = append(b.accounts, accounts...)
+}
+
+// AddTransactions adds transactions to the bank. They're listed newest first,
+// like the API does, regardless of the order they're added in.
+func (b *Bank) AddTransactions(transactions ...upngo.TransactionResource) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.transactions = append(b.transactions, transactions...)
+	sort.SliceStable(b.transactions, func(i, j int) bool {
+		return b.transactions[i].Attributes.CreatedAt.After(b.transactions[j].Attributes.CreatedAt)
+	})
+}
+
+// AddCategories adds categories to the bank. Transactions can only be
+// categorized with categories that have been added.
+func (b *Bank) AddCategories(categories ...upngo.CategoryResource) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.categories = append(b.categories, categories...)
+}
+
+// Transaction gets the transaction with the given ID as it currently is in the
+// bank, e.g. to check the changes made to it.
+func (b *Bank) Transaction(id string) (upngo.TransactionResource, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i := b.transactionIndex(id)
+	if i < 0 {
+		return upngo.TransactionResource{}, false
+	}
+	return b.transactions[i], true
+}
+
+// transactionIndex finds the transaction with the given ID. The lock must be
+// held.
+func (b *Bank) transactionIndex(id string) int {
+	for i, transaction := range b.transactions {
+		if transaction.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// webhookIndex finds the webhook with the given ID. The lock must be held.
+func (b *Bank) webhookIndex(id string) int {
+	for i, webhook := range b.webhooks {
+		if webhook.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// id generates an ID for a new resource. The lock must be held.
+func (b *Bank) id(prefix string) string {
+	b.nextID++
+	return prefix + "-" + strconv.Itoa(b.nextID)
+}
+
+func notFound(kind, id string) error {
+	return fmt.Errorf("%s %s %w", kind, id, ErrNotFound)
+}
+
+// filterTransactions applies the transaction filters in `options`.
+func filterTransactions(transactions []upngo.TransactionResource, options []upngo.TransactionsOption) ([]upngo.TransactionResource, error) {
+	var since, until time.Time
+	var status upngo.TransactionStatus
+	for _, option := range options {
+		var err error
+		switch option.Name() {
+		case "filter[status]":
+			status = upngo.TransactionStatus(option.Value())
+		case "filter[since]":
+			since, err = time.Parse(time.RFC3339, option.Value())
+		case "filter[until]":
+			until, err = time.Parse(time.RFC3339, option.Value())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", option.Name(), err)
+		}
+	}
+
+	// Always return a new slice so callers can't change the bank's.
+	filtered := []upngo.TransactionResource{}
+	for _, transaction := range transactions {
+		createdAt := transaction.Attributes.CreatedAt
+		if !since.IsZero() && createdAt.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !createdAt.Before(until) {
+			continue
+		}
+		if status != "" && transaction.Attributes.Status != status {
+			continue
+		}
+		filtered = append(filtered, transaction)
+	}
+	return filtered, nil
+}
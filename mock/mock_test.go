@@ -0,0 +1,125 @@
+package mock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+func newTransaction(id, accountID string, createdAt time.Time) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.ID = id
+	transaction.Attributes.CreatedAt = createdAt
+	transaction.Relationships.Account.Data.ID = accountID
+	return transaction
+}
+
+func TestTransactions(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2020, 8, d, 0, 0, 0, 0, time.UTC) }
+	bank := New()
+	var account upngo.AccountResource
+	account.ID = "spending"
+	bank.AddAccounts(account)
+	held := newTransaction("2", "spending", day(2))
+	held.Attributes.Status = upngo.TransactionStatusHeld
+	bank.AddTransactions(
+		newTransaction("1", "spending", day(1)),
+		newTransaction("3", "saver", day(3)),
+		held,
+	)
+	var category upngo.CategoryResource
+	category.ID = "takeaway"
+	category.Relationships.Parent.Data = &upngo.DataObject{Type: "categories", ID: "good-life"}
+	bank.AddCategories(category)
+	client := bank.Client()
+
+	transactions, err := client.Transactions.List(upngo.WithFilterSince(day(2)))
+	require.NoError(t, err)
+	require.Len(t, transactions.Data, 2)
+	require.Equal(t, "3", transactions.Data[0].ID)
+	require.Equal(t, "2", transactions.Data[1].ID)
+	_, err = client.Transactions.Next(transactions)
+	require.Equal(t, upngo.ErrNoNextPage, err)
+
+	transactions, err = client.Accounts.Transactions("spending", upngo.WithFilterUntil(day(2)))
+	require.NoError(t, err)
+	require.Len(t, transactions.Data, 1)
+	require.Equal(t, "1", transactions.Data[0].ID)
+
+	transactions, err = client.Transactions.List(upngo.WithFilterStatus(upngo.TransactionStatusHeld))
+	require.NoError(t, err)
+	require.Len(t, transactions.Data, 1)
+	require.Equal(t, "2", transactions.Data[0].ID)
+
+	require.NoError(t, client.Transactions.Categorize("1", "takeaway"))
+	require.NoError(t, client.Transactions.Tag("1", "Holiday", "Work"))
+	require.NoError(t, client.Transactions.Tag("2", "Holiday"))
+	require.NoError(t, client.Transactions.Untag("1", "Work"))
+
+	transaction, ok := bank.Transaction("1")
+	require.True(t, ok)
+	require.Equal(t, "takeaway", transaction.Relationships.Category.Data.ID)
+	require.Equal(t, "good-life", transaction.Relationships.ParentCategory.Data.ID)
+	require.Equal(t, []upngo.DataObject{{Type: "tags", ID: "Holiday"}}, transaction.Relationships.Tags.Data)
+
+	tags, err := client.Tags.List()
+	require.NoError(t, err)
+	require.Equal(t, []upngo.TagResource{{Type: "tags", ID: "Holiday"}}, tags.Data)
+
+	categories, err := client.Categories.List(upngo.WithFilterParent("good-life"))
+	require.NoError(t, err)
+	require.Equal(t, []upngo.CategoryResource{category}, categories.Data)
+	categories, err = client.Categories.List(upngo.WithFilterParent("home"))
+	require.NoError(t, err)
+	require.Empty(t, categories.Data)
+
+	require.True(t, errors.Is(client.Transactions.Categorize("1", "unknown"), ErrNotFound))
+	_, err = client.Transactions.Get("missing")
+	require.True(t, errors.Is(err, ErrNotFound))
+	_, err = client.Accounts.Transactions("missing")
+	require.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestWebhooks(t *testing.T) {
+	client := New().Client()
+
+	created, err := client.Webhooks.Create("https://example.com", upngo.WithDescription("hook"))
+	require.NoError(t, err)
+	require.NotEmpty(t, created.Data.ID)
+	require.NotEmpty(t, created.Data.Attributes.SecretKey)
+	require.Equal(t, "hook", created.Data.Attributes.Description)
+
+	ping, err := client.Webhooks.Ping(created.Data.ID)
+	require.NoError(t, err)
+	require.Equal(t, upngo.WebhookEventTypePing, ping.Data.Attributes.EventType)
+
+	logs, err := client.Webhooks.Logs(created.Data.ID)
+	require.NoError(t, err)
+	require.Len(t, logs.Data, 1)
+	require.Equal(t, ping.Data.ID, logs.Data[0].Relationships.WebhookEvent.Data.ID)
+	require.Equal(t, upngo.WebhookDeliveryStatusDelivered, logs.Data[0].Attributes.DeliveryStatus)
+
+	require.NoError(t, client.Webhooks.Delete(created.Data.ID))
+	webhooks, err := client.Webhooks.List()
+	require.NoError(t, err)
+	require.Empty(t, webhooks.Data)
+	_, err = client.Webhooks.Get(created.Data.ID)
+	require.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestFailWith(t *testing.T) {
+	bank := New()
+	client := bank.Client()
+	boom := errors.New("boom")
+
+	bank.FailWith(boom)
+	_, err := client.Accounts.List()
+	require.Equal(t, boom, err)
+
+	bank.FailWith(nil)
+	_, err = client.Accounts.List()
+	require.NoError(t, err)
+}
@@ -0,0 +1,420 @@
+package mock
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nick96/upngo"
+)
+
+var (
+	_ upngo.AccountsAPI     = (*Accounts)(nil)
+	_ upngo.TransactionsAPI = (*Transactions)(nil)
+	_ upngo.CategoriesAPI   = (*Categories)(nil)
+	_ upngo.TagsAPI         = (*Tags)(nil)
+	_ upngo.WebhooksAPI     = (*Webhooks)(nil)
+)
+
+// Accounts is the bank's accounts service.
+type Accounts struct {
+	bank *Bank
+}
+
+// List lists all the accounts in the bank.
+func (s *Accounts) List(options ...upngo.AccountsOption) (upngo.AccountsResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.AccountsResponse{}, s.bank.err
+	}
+	return upngo.AccountsResponse{Data: append([]upngo.AccountResource{}, s.bank.accounts...)}, nil
+}
+
+// Next always returns upngo.ErrNoNextPage because everything is listed in one
+// page.
+func (s *Accounts) Next(accounts upngo.AccountsResponse) (upngo.AccountsResponse, error) {
+	return upngo.AccountsResponse{}, upngo.ErrNoNextPage
+}
+
+// Get gets the account with the given ID.
+func (s *Accounts) Get(id string) (upngo.AccountResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.AccountResponse{}, s.bank.err
+	}
+	for _, account := range s.bank.accounts {
+		if account.ID == id {
+			return upngo.AccountResponse{Data: account}, nil
+		}
+	}
+	return upngo.AccountResponse{}, notFound("account", id)
+}
+
+// Transactions lists the transactions in the account with the given ID.
+func (s *Accounts) Transactions(accountID string, options ...upngo.TransactionsOption) (upngo.TransactionsResponse, error) {
+	if _, err := s.Get(accountID); err != nil {
+		return upngo.TransactionsResponse{}, err
+	}
+
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	var transactions []upngo.TransactionResource
+	for _, transaction := range s.bank.transactions {
+		if transaction.Relationships.Account.Data.ID == accountID {
+			transactions = append(transactions, transaction)
+		}
+	}
+	transactions, err := filterTransactions(transactions, options)
+	return upngo.TransactionsResponse{Data: transactions}, err
+}
+
+// Transactions is the bank's transactions service.
+type Transactions struct {
+	bank *Bank
+}
+
+// List lists all the transactions in the bank, newest first.
+func (s *Transactions) List(options ...upngo.TransactionsOption) (upngo.TransactionsResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.TransactionsResponse{}, s.bank.err
+	}
+	transactions, err := filterTransactions(s.bank.transactions, options)
+	return upngo.TransactionsResponse{Data: transactions}, err
+}
+
+// Next always returns upngo.ErrNoNextPage because everything is listed in one
+// page.
+func (s *Transactions) Next(transactions upngo.TransactionsResponse) (upngo.TransactionsResponse, error) {
+	return upngo.TransactionsResponse{}, upngo.ErrNoNextPage
+}
+
+// Get gets the transaction with the given ID.
+func (s *Transactions) Get(id string) (upngo.TransactionResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.TransactionResponse{}, s.bank.err
+	}
+	i := s.bank.transactionIndex(id)
+	if i < 0 {
+		return upngo.TransactionResponse{}, notFound("transaction", id)
+	}
+	return upngo.TransactionResponse{Data: s.bank.transactions[i]}, nil
+}
+
+// Categorize sets the category of the transaction, and its parent category.
+// The category has to have been added to the bank. An empty `categoryID`
+// removes the transaction's category.
+func (s *Transactions) Categorize(transactionID string, categoryID string) error {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return s.bank.err
+	}
+	i := s.bank.transactionIndex(transactionID)
+	if i < 0 {
+		return notFound("transaction", transactionID)
+	}
+	relationships := &s.bank.transactions[i].Relationships
+
+	if categoryID == "" {
+		relationships.Category.Data = nil
+		relationships.ParentCategory.Data = nil
+		return nil
+	}
+	for _, category := range s.bank.categories {
+		if category.ID == categoryID {
+			relationships.Category.Data = &upngo.DataObject{Type: "categories", ID: categoryID}
+			relationships.ParentCategory.Data = category.Relationships.Parent.Data
+			return nil
+		}
+	}
+	return notFound("category", categoryID)
+}
+
+// Tag adds the tags to the transaction. Tags that are already on the
+// transaction are left alone.
+func (s *Transactions) Tag(transactionID string, tags ...string) error {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return s.bank.err
+	}
+	i := s.bank.transactionIndex(transactionID)
+	if i < 0 {
+		return notFound("transaction", transactionID)
+	}
+	existing := &s.bank.transactions[i].Relationships.Tags.Data
+	for _, tag := range tags {
+		if !hasTag(*existing, tag) {
+			*existing = append(*existing, upngo.DataObject{Type: "tags", ID: tag})
+		}
+	}
+	return nil
+}
+
+// Untag removes the tags from the transaction.
+func (s *Transactions) Untag(transactionID string, tags ...string) error {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return s.bank.err
+	}
+	i := s.bank.transactionIndex(transactionID)
+	if i < 0 {
+		return notFound("transaction", transactionID)
+	}
+	existing := &s.bank.transactions[i].Relationships.Tags.Data
+	var kept []upngo.DataObject
+	for _, tag := range *existing {
+		if !contains(tags, tag.ID) {
+			kept = append(kept, tag)
+		}
+	}
+	*existing = kept
+	return nil
+}
+
+func hasTag(tags []upngo.DataObject, tag string) bool {
+	for _, existing := range tags {
+		if existing.ID == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Categories is the bank's categories service.
+type Categories struct {
+	bank *Bank
+}
+
+// List lists all the categories in the bank.
+func (s *Categories) List(options ...upngo.CategoriesOption) (upngo.CategoriesResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.CategoriesResponse{}, s.bank.err
+	}
+
+	var parent string
+	for _, option := range options {
+		if option.Name() == "filter[parent]" {
+			parent = option.Value()
+		}
+	}
+	categories := []upngo.CategoryResource{}
+	for _, category := range s.bank.categories {
+		if parent != "" && (category.Relationships.Parent.Data == nil || category.Relationships.Parent.Data.ID != parent) {
+			continue
+		}
+		categories = append(categories, category)
+	}
+	return upngo.CategoriesResponse{Data: categories}, nil
+}
+
+// Get gets the category with the given ID.
+func (s *Categories) Get(id string) (upngo.CategoryResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.CategoryResponse{}, s.bank.err
+	}
+	for _, category := range s.bank.categories {
+		if category.ID == id {
+			return upngo.CategoryResponse{Data: category}, nil
+		}
+	}
+	return upngo.CategoryResponse{}, notFound("category", id)
+}
+
+// Tags is the bank's tags service.
+type Tags struct {
+	bank *Bank
+}
+
+// List lists the tags that are on at least one transaction, in alphabetical
+// order.
+func (s *Tags) List(options ...upngo.TagsOption) (upngo.TagsResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.TagsResponse{}, s.bank.err
+	}
+
+	seen := make(map[string]bool)
+	var labels []string
+	for _, transaction := range s.bank.transactions {
+		for _, tag := range transaction.Relationships.Tags.Data {
+			if !seen[tag.ID] {
+				seen[tag.ID] = true
+				labels = append(labels, tag.ID)
+			}
+		}
+	}
+	sort.Strings(labels)
+
+	tags := []upngo.TagResource{}
+	for _, label := range labels {
+		tags = append(tags, upngo.TagResource{Type: "tags", ID: label})
+	}
+	return upngo.TagsResponse{Data: tags}, nil
+}
+
+// Next always returns upngo.ErrNoNextPage because everything is listed in one
+// page.
+func (s *Tags) Next(tags upngo.TagsResponse) (upngo.TagsResponse, error) {
+	return upngo.TagsResponse{}, upngo.ErrNoNextPage
+}
+
+// Webhooks is the bank's webhooks service. Webhooks don't receive any events,
+// but pinging one records a delivery log like the API does.
+type Webhooks struct {
+	bank *Bank
+}
+
+// List lists all the webhooks in the bank.
+func (s *Webhooks) List(options ...upngo.WebhooksOption) (upngo.WebhooksResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.WebhooksResponse{}, s.bank.err
+	}
+	return upngo.WebhooksResponse{Data: append([]upngo.WebhookResource{}, s.bank.webhooks...)}, nil
+}
+
+// Next always returns upngo.ErrNoNextPage because everything is listed in one
+// page.
+func (s *Webhooks) Next(webhooks upngo.WebhooksResponse) (upngo.WebhooksResponse, error) {
+	return upngo.WebhooksResponse{}, upngo.ErrNoNextPage
+}
+
+// Get gets the webhook with the given ID.
+func (s *Webhooks) Get(id string) (upngo.WebhookResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.WebhookResponse{}, s.bank.err
+	}
+	i := s.bank.webhookIndex(id)
+	if i < 0 {
+		return upngo.WebhookResponse{}, notFound("webhook", id)
+	}
+	return upngo.WebhookResponse{Data: s.bank.webhooks[i]}, nil
+}
+
+// Create adds a webhook for the given URL to the bank. It's given a generated
+// ID and secret key.
+func (s *Webhooks) Create(webhookURL string, options ...upngo.RegisterWebhookOption) (upngo.WebhookResponse, error) {
+	var description string
+	for _, option := range options {
+		if option.Name() == "description" {
+			description = option.Value()
+		}
+	}
+
+	if len(webhookURL) > upngo.MaxWebhookURLLength {
+		return upngo.WebhookResponse{}, fmt.Errorf("webhook URL too long. Max length is %d", upngo.MaxWebhookURLLength)
+	}
+
+	if len(description) > upngo.MaxWebhookDescriptionLength {
+		return upngo.WebhookResponse{}, fmt.Errorf("webhook description too long. Max length is %d", upngo.MaxWebhookDescriptionLength)
+	}
+
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.WebhookResponse{}, s.bank.err
+	}
+
+	webhook := upngo.WebhookResource{
+		Type: "webhooks",
+		ID:   s.bank.id("webhook"),
+		Attributes: upngo.WebhooksAttributes{
+			URL:         webhookURL,
+			Description: description,
+			SecretKey:   s.bank.id("secret"),
+			CreatedAt:   s.bank.now(),
+		},
+	}
+	s.bank.webhooks = append(s.bank.webhooks, webhook)
+	return upngo.WebhookResponse{Data: webhook}, nil
+}
+
+// Delete removes the webhook with the given ID and its logs.
+func (s *Webhooks) Delete(id string) error {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return s.bank.err
+	}
+	i := s.bank.webhookIndex(id)
+	if i < 0 {
+		return notFound("webhook", id)
+	}
+	s.bank.webhooks = append(s.bank.webhooks[:i], s.bank.webhooks[i+1:]...)
+	delete(s.bank.logs, id)
+	return nil
+}
+
+// Ping creates a ping event for the webhook and records it as delivered.
+func (s *Webhooks) Ping(id string) (upngo.WebhookPingResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.WebhookPingResponse{}, s.bank.err
+	}
+	if s.bank.webhookIndex(id) < 0 {
+		return upngo.WebhookPingResponse{}, notFound("webhook", id)
+	}
+
+	var event upngo.WebhookEventResource
+	event.Type = "webhook-events"
+	event.ID = s.bank.id("event")
+	event.Attributes.EventType = upngo.WebhookEventTypePing
+	event.Attributes.CreatedAt = s.bank.now()
+	event.Relationships.Webhook.Data.Type = "webhooks"
+	event.Relationships.Webhook.Data.ID = id
+
+	var log upngo.WebhookDeliveryLogResource
+	log.Type = "webhook-delivery-logs"
+	log.ID = s.bank.id("log")
+	log.Attributes.DeliveryStatus = upngo.WebhookDeliveryStatusDelivered
+	log.Attributes.CreatedAt = event.Attributes.CreatedAt
+	log.Relationships.WebhookEvent.Data = upngo.DataObject{Type: "webhook-events", ID: event.ID}
+	// Logs are listed newest first.
+	s.bank.logs[id] = append([]upngo.WebhookDeliveryLogResource{log}, s.bank.logs[id]...)
+
+	return upngo.WebhookPingResponse{Data: event}, nil
+}
+
+// Logs lists the delivery logs of the webhook with the given ID, newest first.
+func (s *Webhooks) Logs(id string, options ...upngo.WebhookLogsOption) (upngo.WebhookLogsResponse, error) {
+	s.bank.mu.Lock()
+	defer s.bank.mu.Unlock()
+	if s.bank.err != nil {
+		return upngo.WebhookLogsResponse{}, s.bank.err
+	}
+	if s.bank.webhookIndex(id) < 0 {
+		return upngo.WebhookLogsResponse{}, notFound("webhook", id)
+	}
+	return upngo.WebhookLogsResponse{Data: append([]upngo.WebhookDeliveryLogResource{}, s.bank.logs[id]...)}, nil
+}
+
+// NextLogs always returns upngo.ErrNoNextPage because everything is listed in
+// one page.
+func (s *Webhooks) NextLogs(logs upngo.WebhookLogsResponse) (upngo.WebhookLogsResponse, error) {
+	return upngo.WebhookLogsResponse{}, upngo.ErrNoNextPage
+}
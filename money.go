@@ -0,0 +1,63 @@
+package upngo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/currency"
+)
+
+// currencyScale is the number of decimal places in the currency's base units,
+// e.g. 2 for AUD because there are 100 cents in a dollar.
+func currencyScale(currencyCode string) int {
+	unit, err := currency.ParseISO(currencyCode)
+	if err != nil {
+		return 2
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return scale
+}
+
+// FormatBaseUnits formats an amount in the currency's base units (e.g. cents)
+// as a decimal. It doesn't go through a float so it's exact.
+func FormatBaseUnits(baseUnits int64, currencyCode string) string {
+	scale := currencyScale(currencyCode)
+
+	sign := ""
+	if baseUnits < 0 {
+		sign = "-"
+		baseUnits = -baseUnits
+	}
+	digits := fmt.Sprintf("%0*d", scale+1, baseUnits)
+	if scale == 0 {
+		return sign + digits
+	}
+	split := len(digits) - scale
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// ParseBaseUnits parses a decimal amount, like "12.34", into the currency's
+// base units. It doesn't go through a float so it's exact.
+func ParseBaseUnits(value string, currencyCode string) (int64, error) {
+	scale := currencyScale(currencyCode)
+
+	whole, fraction := value, ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		whole, fraction = value[:i], value[i+1:]
+	}
+	// Without this "", "." and "-" would all parse as zero.
+	if strings.TrimLeft(whole, "+-")+fraction == "" {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	if len(fraction) > scale {
+		return 0, fmt.Errorf("%s has more than %d decimal places", value, scale)
+	}
+	fraction += strings.Repeat("0", scale-len(fraction))
+
+	baseUnits, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %s", value)
+	}
+	return baseUnits, nil
+}
@@ -0,0 +1,137 @@
+// Package networth works out account balances over time and adds them up into
+// a net worth.
+//
+// The API only gives an account's current balance, so past balances are
+// reconstructed by walking backwards through the account's transactions and
+// undoing each one.
+package networth
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+// ErrMixedCurrencies is returned when combining balances in different
+// currencies, which can't be added up.
+var ErrMixedCurrencies = errors.New("balances are in different currencies")
+
+// Point is a balance at the end of a day. Date is the calendar day, at
+// midnight UTC, so it means the same thing whichever timezone it was worked
+// out in. Balance is in the currency's base units.
+type Point struct {
+	Date    time.Time
+	Balance int64
+}
+
+// Series is the daily balances of an account, oldest first.
+type Series struct {
+	AccountID string
+	Currency  string
+	Points    []Point
+}
+
+// Day is the calendar day `t` is on in `loc`, at midnight UTC.
+func Day(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// endOfDay is the instant the day after `day` starts in `loc`.
+func endOfDay(day time.Time, loc *time.Location) time.Time {
+	next := day.AddDate(0, 0, 1)
+	return time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, loc)
+}
+
+// History reconstructs the balance of `account` at the end of each day from
+// `since` to `now`, in `loc`. The last point is the account's current
+// balance. Days before the account was created are left out.
+//
+// `transactions` has to include every transaction in the account since the
+// start of `since`, otherwise the balances will be off. Transactions in other
+// accounts are ignored so it's fine to pass all of them.
+func History(account upngo.AccountResource, transactions []upngo.TransactionResource, since, now time.Time, loc *time.Location) Series {
+	series := Series{
+		AccountID: account.ID,
+		Currency:  account.Attributes.Balance.CurrencyCode,
+	}
+
+	first := Day(since, loc)
+	if created := account.Attributes.CreatedAt; !created.IsZero() && Day(created, loc).After(first) {
+		first = Day(created, loc)
+	}
+
+	var amounts []upngo.TransactionResource
+	for _, transaction := range transactions {
+		if transaction.Relationships.Account.Data.ID == account.ID {
+			amounts = append(amounts, transaction)
+		}
+	}
+	sort.SliceStable(amounts, func(i, j int) bool {
+		return amounts[i].Attributes.CreatedAt.After(amounts[j].Attributes.CreatedAt)
+	})
+
+	// Walk back a day at a time, undoing the transactions made after the end
+	// of each day to get the balance it ended on.
+	balance := account.Attributes.Balance.ValueInBaseUnits
+	next := 0
+	for day := Day(now, loc); !day.Before(first); day = day.AddDate(0, 0, -1) {
+		end := endOfDay(day, loc)
+		for next < len(amounts) && !amounts[next].Attributes.CreatedAt.Before(end) {
+			balance -= amounts[next].Attributes.Amount.ValueInBaseUnits
+			next++
+		}
+		series.Points = append(series.Points, Point{Date: day, Balance: balance})
+	}
+
+	// They were worked out newest first.
+	for i, j := 0, len(series.Points)-1; i < j; i, j = i+1, j-1 {
+		series.Points[i], series.Points[j] = series.Points[j], series.Points[i]
+	}
+	return series
+}
+
+// Combine adds up the balances of each series into a net worth for every day
+// that any of them has a balance for. Before its first point a series counts
+// as zero, because the account didn't exist yet, and after its last point its
+// last balance carries on.
+func Combine(series ...Series) (Series, error) {
+	var combined Series
+	days := make(map[time.Time]bool)
+	for _, s := range series {
+		if len(s.Points) == 0 {
+			continue
+		}
+		if combined.Currency == "" {
+			combined.Currency = s.Currency
+		} else if s.Currency != combined.Currency {
+			return Series{}, ErrMixedCurrencies
+		}
+		for _, point := range s.Points {
+			days[point.Date] = true
+		}
+	}
+
+	var dates []time.Time
+	for day := range days {
+		dates = append(dates, day)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	for _, day := range dates {
+		combined.Points = append(combined.Points, Point{Date: day, Balance: 0})
+	}
+	for _, s := range series {
+		next, balance := 0, int64(0)
+		for i, day := range dates {
+			for next < len(s.Points) && !s.Points[next].Date.After(day) {
+				balance = s.Points[next].Balance
+				next++
+			}
+			combined.Points[i].Balance += balance
+		}
+	}
+	return combined, nil
+}
@@ -0,0 +1,76 @@
+package networth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+func newTransaction(accountID string, amount int64, createdAt time.Time) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.Attributes.Amount = upngo.MoneyObject{CurrencyCode: "AUD", ValueInBaseUnits: amount}
+	transaction.Attributes.CreatedAt = createdAt
+	transaction.Relationships.Account.Data.ID = accountID
+	return transaction
+}
+
+func day(d int) time.Time {
+	return time.Date(2020, 8, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestHistory(t *testing.T) {
+	loc, err := time.LoadLocation("Australia/Melbourne")
+	require.NoError(t, err)
+	at := func(d, hour int) time.Time { return time.Date(2020, 8, d, hour, 0, 0, 0, loc) }
+
+	var account upngo.AccountResource
+	account.ID = "spending"
+	account.Attributes.Balance = upngo.MoneyObject{CurrencyCode: "AUD", ValueInBaseUnits: 10000}
+	account.Attributes.CreatedAt = at(2, 9)
+
+	transactions := []upngo.TransactionResource{
+		newTransaction("spending", 5000, at(2, 10)),
+		newTransaction("spending", -2000, at(3, 23)),
+		// Late at night in Melbourne but the next day in UTC, it's still the
+		// 4th.
+		newTransaction("spending", 7000, at(4, 23)),
+		newTransaction("saver", 100000, at(4, 12)),
+	}
+
+	series := History(account, transactions, at(1, 0), at(5, 12), loc)
+	require.Equal(t, "spending", series.AccountID)
+	require.Equal(t, "AUD", series.Currency)
+	// The account didn't exist on the 1st.
+	require.Equal(t, []Point{
+		{Date: day(2), Balance: 5000},
+		{Date: day(3), Balance: 3000},
+		{Date: day(4), Balance: 10000},
+		{Date: day(5), Balance: 10000},
+	}, series.Points)
+}
+
+func TestCombine(t *testing.T) {
+	spending := Series{AccountID: "spending", Currency: "AUD", Points: []Point{
+		{Date: day(1), Balance: 100},
+		{Date: day(2), Balance: 200},
+		{Date: day(3), Balance: 300},
+	}}
+	// Opened later, and not snapshotted on the 3rd.
+	saver := Series{AccountID: "saver", Currency: "AUD", Points: []Point{
+		{Date: day(2), Balance: 1000},
+	}}
+
+	combined, err := Combine(spending, saver)
+	require.NoError(t, err)
+	require.Equal(t, "AUD", combined.Currency)
+	require.Equal(t, []Point{
+		{Date: day(1), Balance: 100},
+		{Date: day(2), Balance: 1200},
+		{Date: day(3), Balance: 1300},
+	}, combined.Points)
+
+	_, err = Combine(spending, Series{Currency: "USD", Points: []Point{{Date: day(1)}}})
+	require.Equal(t, ErrMixedCurrencies, err)
+}
@@ -0,0 +1,152 @@
+package upngo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestInfo describes one attempt at a request to the API.
+type RequestInfo struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// Endpoint is the path of the request with IDs replaced by `{id}`, e.g.
+	// `/accounts/{id}/transactions`, so it can be used as a metric label.
+	Endpoint string
+	// StatusCode is the status code of the response, or 0 if there wasn't
+	// one.
+	StatusCode int
+	// Duration is how long the attempt took.
+	Duration time.Duration
+	// Attempt is which attempt this was, starting at 1. Anything greater is
+	// a retry.
+	Attempt int
+	// Err is the error sending the request, if there was one.
+	Err error
+}
+
+// Observer is told about every request the client makes, including retries.
+// It's called from whichever goroutine made the request so it must be safe
+// for concurrent use.
+type Observer interface {
+	ObserveRequest(info RequestInfo)
+}
+
+// ObserverFunc lets an ordinary function be used as an Observer.
+type ObserverFunc func(info RequestInfo)
+
+// ObserveRequest calls f(info).
+func (f ObserverFunc) ObserveRequest(info RequestInfo) {
+	f(info)
+}
+
+// Tracer starts spans. It's shaped like the OpenTelemetry tracer so one can
+// be wrapped to satisfy it.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced operation. There's a span for each request the client
+// makes, covering all of its attempts.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// NoopTracer is a Tracer whose spans do nothing. It's the default.
+type NoopTracer struct{}
+
+// Start returns `ctx` and a span that does nothing.
+func (NoopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) RecordError(err error)                      {}
+func (noopSpan) End()                                       {}
+
+// WithObserver makes the client tell `observer` about every request it
+// makes.
+func WithObserver(observer Observer) ClientOption {
+	return func(c *Client) {
+		c.observer = observer
+	}
+}
+
+// WithTracer makes the client start a span with `tracer` for each request.
+// The span is a child of whatever span is in the request's context.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// WithRetries makes the client retry requests that fail because of a network
+// error, a 429 or a 5xx up to `max` times. It waits `backoff` before the first
+// retry and doubles the wait each time after, unless the API says how long to
+// wait with a Retry-After header. Only idempotent requests are retried.
+func WithRetries(max int, backoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxRetries = max
+		c.retryBackoff = backoff
+	}
+}
+
+// WithMiddleware wraps the client's transport with each of `middleware`, in
+// order, so the last one sees each request first. They see requests after the
+// authorization header is added but before they're retried, rate limited,
+// observed or logged.
+func WithMiddleware(middleware ...func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// resourceSegments are the parts of API paths that aren't IDs.
+var resourceSegments = map[string]bool{
+	"accounts":      true,
+	"transactions":  true,
+	"categories":    true,
+	"category":      true,
+	"tags":          true,
+	"webhooks":      true,
+	"logs":          true,
+	"ping":          true,
+	"util":          true,
+	"relationships": true,
+}
+
+// Endpoint turns the path of `req` into a template with the IDs replaced by
+// `{id}`, e.g. `/api/v1/accounts/123/transactions` becomes
+// `/accounts/{id}/transactions`.
+func Endpoint(req *http.Request) string {
+	path := strings.Trim(strings.TrimPrefix(req.URL.Path, "/api/v1"), "/")
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !resourceSegments[segment] {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+type attemptKey struct{}
+
+// withAttempt records in the context which attempt at a request this is.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+func attemptFrom(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
+}
@@ -0,0 +1,14 @@
+package upngo
+
+import "strings"
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithBaseURL makes the client send requests to `baseURL` instead of the real
+// API, e.g. to a test server.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parsePeriod parses a date value into the period [start, end) it covers.
+// The value is either a single period or a range of periods, `a..b`, which
+// covers from the start of `a` to the end of `b`.
+func parsePeriod(value string, now time.Time) (time.Time, time.Time, error) {
+	if parts := strings.SplitN(value, "..", 2); len(parts) == 2 {
+		start, _, err := parseSinglePeriod(parts[0], now)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		_, end, err := parseSinglePeriod(parts[1], now)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return start, end, nil
+	}
+	return parseSinglePeriod(value, now)
+}
+
+func parseSinglePeriod(value string, now time.Time) (time.Time, time.Time, error) {
+	location := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	// Go's weeks start on Sunday but weeks start on Monday in Australia.
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+	quarterStart := time.Date(now.Year(), now.Month()-(now.Month()-1)%3, 1, 0, 0, 0, 0, location)
+	yearStart := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, location)
+
+	switch strings.ToLower(value) {
+	case "today":
+		return today, today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, nil
+	case "this-week":
+		return weekStart, weekStart.AddDate(0, 0, 7), nil
+	case "last-week":
+		return weekStart.AddDate(0, 0, -7), weekStart, nil
+	case "this-month":
+		return monthStart, monthStart.AddDate(0, 1, 0), nil
+	case "last-month":
+		return monthStart.AddDate(0, -1, 0), monthStart, nil
+	case "this-quarter":
+		return quarterStart, quarterStart.AddDate(0, 3, 0), nil
+	case "last-quarter":
+		return quarterStart.AddDate(0, -3, 0), quarterStart, nil
+	case "this-year":
+		return yearStart, yearStart.AddDate(1, 0, 0), nil
+	case "last-year":
+		return yearStart.AddDate(-1, 0, 0), yearStart, nil
+	}
+
+	layouts := []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	}
+	for _, l := range layouts {
+		start, err := time.ParseInLocation(l.layout, value, location)
+		if err == nil {
+			return start, start.AddDate(l.years, l.months, l.days), nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q", value)
+}
@@ -0,0 +1,158 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLeftParen
+	tokenRightParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	// tokenTerm is a `field<op>value` term or, if `field` and `op` are empty,
+	// a bare word to search for.
+	tokenTerm
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+
+	field string
+	op    string
+	value string
+	// regex is true if the value was written as `/.../`.
+	regex bool
+}
+
+// operators are the operators that can separate a field from its value. They
+// are ordered so that the longest match comes first.
+var operators = []string{">=", "<=", "!=", ":", "~", "=", ">", "<"}
+
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func (l *lexer) peek() rune {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos]
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+}
+
+func (l *lexer) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(l.input[l.pos:]), prefix)
+}
+
+// isTermEnd is true for the characters that end an unquoted value.
+func isTermEnd(r rune) bool {
+	return r == 0 || r == '(' || r == ')' || unicode.IsSpace(r)
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	start := l.pos
+
+	switch r := l.peek(); {
+	case r == 0:
+		return token{kind: tokenEOF, pos: start}, nil
+	case r == '(':
+		l.pos++
+		return token{kind: tokenLeftParen, pos: start}, nil
+	case r == ')':
+		l.pos++
+		return token{kind: tokenRightParen, pos: start}, nil
+	case r == '-':
+		l.pos++
+		return token{kind: tokenNot, pos: start}, nil
+	case r == '"':
+		value, err := l.quoted('"')
+		return token{kind: tokenTerm, pos: start, value: value}, err
+	}
+
+	var field strings.Builder
+	for unicode.IsLetter(l.peek()) || unicode.IsDigit(l.peek()) || l.peek() == '_' {
+		field.WriteRune(l.peek())
+		l.pos++
+	}
+
+	for _, op := range operators {
+		if field.Len() > 0 && l.hasPrefix(op) {
+			l.pos += len(op)
+			tok := token{kind: tokenTerm, pos: start, field: strings.ToLower(field.String()), op: op}
+			var err error
+			switch l.peek() {
+			case '"':
+				tok.value, err = l.quoted('"')
+			case '/':
+				tok.value, err = l.quoted('/')
+				tok.regex = true
+			default:
+				tok.value = l.bare()
+			}
+			if err == nil && tok.value == "" {
+				err = fmt.Errorf("missing value for %s at position %d", tok.field, start)
+			}
+			return tok, err
+		}
+	}
+
+	// Not a field so it's a bare word, which could be one of the keywords.
+	word := field.String() + l.bare()
+	switch word {
+	case "AND":
+		return token{kind: tokenAnd, pos: start}, nil
+	case "OR":
+		return token{kind: tokenOr, pos: start}, nil
+	case "NOT":
+		return token{kind: tokenNot, pos: start}, nil
+	}
+	return token{kind: tokenTerm, pos: start, value: word}, nil
+}
+
+// bare reads an unquoted value.
+func (l *lexer) bare() string {
+	start := l.pos
+	for !isTermEnd(l.peek()) {
+		l.pos++
+	}
+	return string(l.input[start:l.pos])
+}
+
+// quoted reads a value delimited by `delim`. A backslash escapes the
+// delimiter, everything else is taken literally.
+func (l *lexer) quoted(delim rune) (string, error) {
+	start := l.pos
+	l.pos++
+
+	var value strings.Builder
+	for {
+		r := l.peek()
+		switch {
+		case r == 0:
+			return "", fmt.Errorf("unterminated %c at position %d", delim, start)
+		case r == '\\' && l.pos+1 < len(l.input) && l.input[l.pos+1] == delim:
+			value.WriteRune(delim)
+			l.pos += 2
+		case r == delim:
+			l.pos++
+			return value.String(), nil
+		default:
+			value.WriteRune(r)
+			l.pos++
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+// node is a node in the parsed query's syntax tree.
+type node interface {
+	match(e Evaluator, transaction upngo.TransactionResource) bool
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) match(e Evaluator, transaction upngo.TransactionResource) bool {
+	return n.left.match(e, transaction) && n.right.match(e, transaction)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) match(e Evaluator, transaction upngo.TransactionResource) bool {
+	return n.left.match(e, transaction) || n.right.match(e, transaction)
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) match(e Evaluator, transaction upngo.TransactionResource) bool {
+	return !n.operand.match(e, transaction)
+}
+
+// termNode is a single term that has been compiled into a predicate.
+type termNode struct {
+	predicate func(e Evaluator, transaction upngo.TransactionResource) bool
+}
+
+func (n termNode) match(e Evaluator, transaction upngo.TransactionResource) bool {
+	return n.predicate(e, transaction)
+}
+
+// parser is a recursive descent parser for the grammar:
+//
+//	query   = or EOF
+//	or      = and { "OR" and }
+//	and     = unary { [ "AND" ] unary }
+//	unary   = ( "NOT" | "-" ) unary | primary
+//	primary = "(" or ")" | term
+type parser struct {
+	lexer lexer
+	now   time.Time
+	// current is the token we're looking at. It hasn't been consumed yet.
+	current token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.current = tok
+	return nil
+}
+
+func (p *parser) parse() (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.current.kind == tokenEOF {
+		// An empty query matches everything.
+		return nil, nil
+	}
+
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.current.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected input at position %d", p.current.pos)
+	}
+	return root, nil
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.current.kind == tokenOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.current.kind {
+		case tokenAnd:
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		case tokenTerm, tokenNot, tokenLeftParen:
+			// Terms next to each other are implicitly ANDed.
+		case tokenEOF, tokenOr, tokenRightParen:
+			return left, nil
+		}
+
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if p.current.kind == tokenNot {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	tok := p.current
+	switch tok.kind {
+	case tokenLeftParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.current.kind != tokenRightParen {
+			return nil, fmt.Errorf("missing ) for ( at position %d", tok.pos)
+		}
+		return inner, p.advance()
+	case tokenTerm:
+		predicate, err := compileTerm(tok, p.now)
+		if err != nil {
+			return nil, err
+		}
+		return termNode{predicate}, p.advance()
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of query")
+	case tokenRightParen, tokenAnd, tokenOr, tokenNot:
+		return nil, fmt.Errorf("unexpected input at position %d", tok.pos)
+	}
+	return nil, fmt.Errorf("unexpected input at position %d", tok.pos)
+}
@@ -0,0 +1,107 @@
+// Package query implements a small query language for searching transactions.
+//
+// A query is made up of terms which are implicitly ANDed together. Terms can be
+// combined with `OR`, negated with `NOT` or a leading `-`, and grouped with
+// parentheses. A bare word, or "quoted phrase", matches transactions that have
+// it in their description, raw text or message. Otherwise a term is a field,
+// an operator and a value, e.g.
+//
+//	uber amount>20 date:last-quarter
+//	description~/^(uber|didi)/ -status:held
+//	(category:takeaway OR tag:Holiday) foreign:USD
+//
+// The fields are:
+//
+//	description, rawtext, message, text  ':' substring, '=' exact, '~' regex
+//	amount          the size of the amount, compared with ':' (a range, e.g.
+//	                20..50), '=', '!=', '>', '>=', '<' and '<='
+//	direction       'in' for money coming in, 'out' for money going out
+//	date            when the transaction was created, e.g. 2020, 2020-07,
+//	                2020-07-01, 2020-07-01..2020-09-30, today, yesterday,
+//	                this-week, last-month, last-quarter, this-year, etc.
+//	status          held or settled
+//	account         the account ID or display name
+//	category        the category or parent category ID
+//	tag             one of the transaction's tags
+//	currency        the currency of the amount or foreign amount
+//	foreign         a foreign currency code, or yes/no for whether there is one
+//
+// Text matching is case insensitive.
+package query
+
+import (
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+// Query is a parsed query.
+type Query struct {
+	input string
+	root  node
+}
+
+// String returns the query as it was given to Parse.
+func (q *Query) String() string {
+	return q.input
+}
+
+// Match reports whether the transaction matches the query. It uses an
+// Evaluator with no extra information, so accounts are only matched by ID.
+func (q *Query) Match(transaction upngo.TransactionResource) bool {
+	return Evaluator{}.Match(q, transaction)
+}
+
+// Evaluator evaluates queries against transactions.
+type Evaluator struct {
+	// AccountNames maps account IDs to their display names so `account:` can
+	// match by name as well as ID.
+	AccountNames map[string]string
+}
+
+// NewEvaluator creates an evaluator that knows about the given accounts.
+func NewEvaluator(accounts []upngo.AccountResource) Evaluator {
+	names := make(map[string]string, len(accounts))
+	for _, account := range accounts {
+		names[account.ID] = account.Attributes.DisplayName
+	}
+	return Evaluator{AccountNames: names}
+}
+
+// Match reports whether the transaction matches the query.
+func (e Evaluator) Match(q *Query, transaction upngo.TransactionResource) bool {
+	if q.root == nil {
+		return true
+	}
+	return q.root.match(e, transaction)
+}
+
+// Filter returns the transactions that match the query, in the same order.
+func (e Evaluator) Filter(q *Query, transactions []upngo.TransactionResource) []upngo.TransactionResource {
+	var matches []upngo.TransactionResource
+	for _, transaction := range transactions {
+		if e.Match(q, transaction) {
+			matches = append(matches, transaction)
+		}
+	}
+	return matches
+}
+
+// Parse parses the query. Relative dates, like `date:this-month`, are relative
+// to the current time.
+func Parse(input string) (*Query, error) {
+	return ParseAt(input, time.Now())
+}
+
+// ParseAt parses the query with relative dates being relative to `now`.
+func ParseAt(input string, now time.Time) (*Query, error) {
+	p := parser{
+		lexer: lexer{input: []rune(input)},
+		now:   now,
+	}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Query{input: input, root: root}, nil
+}
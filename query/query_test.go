@@ -0,0 +1,112 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+// now is a Wednesday in the middle of a quarter.
+var now = time.Date(2020, 8, 19, 12, 0, 0, 0, time.UTC)
+
+func newTransaction(description, amount string, createdAt time.Time) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.ID = description
+	transaction.Attributes.Description = description
+	transaction.Attributes.RawText = "RAW " + description
+	transaction.Attributes.Amount = upngo.MoneyObject{CurrencyCode: "AUD", Value: amount}
+	if amount[0] == '-' {
+		transaction.Attributes.Amount.ValueInBaseUnits = -1
+	} else {
+		transaction.Attributes.Amount.ValueInBaseUnits = 1
+	}
+	transaction.Attributes.Status = "SETTLED"
+	transaction.Attributes.CreatedAt = createdAt
+	transaction.Relationships.Account.Data.ID = "spending-id"
+	return transaction
+}
+
+func TestMatch(t *testing.T) {
+	uber := newTransaction("Uber", "-25.50", time.Date(2020, 5, 10, 0, 0, 0, 0, time.UTC))
+	uber.Relationships.Category.Data = &upngo.DataObject{Type: "categories", ID: "taxis-and-share-cars"}
+	uber.Relationships.ParentCategory.Data = &upngo.DataObject{Type: "categories", ID: "transport"}
+
+	uberEats := newTransaction("Uber Eats", "-12.00", time.Date(2020, 8, 18, 0, 0, 0, 0, time.UTC))
+	uberEats.Attributes.Status = upngo.TransactionStatusHeld
+	uberEats.Relationships.Tags.Data = []upngo.DataObject{{Type: "tags", ID: "Holiday"}}
+
+	pay := newTransaction("Salary", "1000.00", time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC))
+	pay.Attributes.Message = "thanks boss"
+
+	hotel := newTransaction("Hotel", "-150.00", time.Date(2020, 7, 3, 0, 0, 0, 0, time.UTC))
+	hotel.Attributes.ForeignAmount = upngo.MoneyObject{CurrencyCode: "USD", Value: "-100.00"}
+
+	transactions := []upngo.TransactionResource{uber, uberEats, pay, hotel}
+	evaluator := NewEvaluator([]upngo.AccountResource{
+		{ID: "spending-id", Attributes: upngo.AttributesObject{DisplayName: "Spending"}},
+	})
+
+	testCases := []struct {
+		query    string
+		expected []upngo.TransactionResource
+	}{
+		{"", transactions},
+		{"uber", []upngo.TransactionResource{uber, uberEats}},
+		{"uber amount>20", []upngo.TransactionResource{uber}},
+		{"uber AND amount<=$12", []upngo.TransactionResource{uberEats}},
+		{`"uber eats"`, []upngo.TransactionResource{uberEats}},
+		{"description=uber", []upngo.TransactionResource{uber}},
+		{"desc~/^uber$/", []upngo.TransactionResource{uber}},
+		{`rawtext~"raw (hotel|salary)"`, []upngo.TransactionResource{pay, hotel}},
+		{"msg:BOSS", []upngo.TransactionResource{pay}},
+		{"amount:100..200", []upngo.TransactionResource{hotel}},
+		{"amount=1000", []upngo.TransactionResource{pay}},
+		{"direction:in", []upngo.TransactionResource{pay}},
+		{"-direction:in", []upngo.TransactionResource{uber, uberEats, hotel}},
+		{"date:last-quarter", []upngo.TransactionResource{uber}},
+		{"date:this-week", []upngo.TransactionResource{uberEats}},
+		{"date:2020-07", []upngo.TransactionResource{hotel}},
+		{"date:2020-07..2020-08-01", []upngo.TransactionResource{pay, hotel}},
+		{"date>2020-07", []upngo.TransactionResource{uberEats, pay}},
+		{"date<2020-07-03", []upngo.TransactionResource{uber}},
+		{"status:held", []upngo.TransactionResource{uberEats}},
+		{"NOT status:held", []upngo.TransactionResource{uber, pay, hotel}},
+		{"category:transport", []upngo.TransactionResource{uber}},
+		{"tag:holiday OR foreign:usd", []upngo.TransactionResource{uberEats, hotel}},
+		{"foreign:no direction:out", []upngo.TransactionResource{uber, uberEats}},
+		{"currency:USD", []upngo.TransactionResource{hotel}},
+		{"account:spending-id amount>500", []upngo.TransactionResource{pay}},
+		{"account:Spending (hotel OR salary)", []upngo.TransactionResource{pay, hotel}},
+		{"-(uber OR hotel)", []upngo.TransactionResource{pay}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.query, func(t *testing.T) {
+			q, err := ParseAt(testCase.query, now)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, evaluator.Filter(q, transactions))
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{
+		"colour:red",
+		"amount>lots",
+		"date:someday",
+		"direction:sideways",
+		`desc~/(unclosed/`,
+		`"unterminated`,
+		"(uber",
+		"uber)",
+		"uber OR",
+		"status>held",
+		"amount:",
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, err := ParseAt(query, now)
+			require.Error(t, err)
+		})
+	}
+}
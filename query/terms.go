@@ -0,0 +1,281 @@
+package query
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+type predicate func(e Evaluator, transaction upngo.TransactionResource) bool
+
+// textFields gets the text that each text field matches against.
+var textFields = map[string]func(transaction upngo.TransactionResource) []string{
+	"description": func(t upngo.TransactionResource) []string { return []string{t.Attributes.Description} },
+	"rawtext":     func(t upngo.TransactionResource) []string { return []string{t.Attributes.RawText} },
+	"message":     func(t upngo.TransactionResource) []string { return []string{t.Attributes.Message} },
+	"text": func(t upngo.TransactionResource) []string {
+		return []string{t.Attributes.Description, t.Attributes.RawText, t.Attributes.Message}
+	},
+}
+
+// fieldAliases are shorter names for fields.
+var fieldAliases = map[string]string{
+	"desc": "description",
+	"raw":  "rawtext",
+	"msg":  "message",
+}
+
+func compileTerm(tok token, now time.Time) (predicate, error) {
+	field := tok.field
+	if field == "" {
+		field, tok.op = "text", ":"
+	}
+	if alias, ok := fieldAliases[field]; ok {
+		field = alias
+	}
+
+	var (
+		p   predicate
+		err error
+	)
+	if get, ok := textFields[field]; ok {
+		p, err = compileText(tok, get)
+	} else {
+		switch field {
+		case "amount":
+			p, err = compileAmount(tok)
+		case "direction":
+			p, err = compileDirection(tok)
+		case "date":
+			p, err = compileDate(tok, now)
+		case "status", "account", "category", "tag", "currency", "foreign":
+			p, err = compileEquality(field, tok)
+		default:
+			return nil, fmt.Errorf("unknown field %q at position %d", tok.field, tok.pos)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s term at position %d: %w", field, tok.pos, err)
+	}
+	return p, nil
+}
+
+func compileText(tok token, get func(upngo.TransactionResource) []string) (predicate, error) {
+	var matches func(text string) bool
+	switch {
+	case tok.op == "~" || tok.regex && tok.op == ":":
+		re, err := regexp.Compile("(?i)" + tok.value)
+		if err != nil {
+			return nil, err
+		}
+		matches = re.MatchString
+	case tok.op == ":":
+		value := strings.ToLower(tok.value)
+		matches = func(text string) bool { return strings.Contains(strings.ToLower(text), value) }
+	case tok.op == "=" || tok.op == "!=":
+		matches = func(text string) bool { return strings.EqualFold(text, tok.value) }
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", tok.op)
+	}
+
+	p := func(_ Evaluator, transaction upngo.TransactionResource) bool {
+		for _, text := range get(transaction) {
+			if matches(text) {
+				return true
+			}
+		}
+		return false
+	}
+	if tok.op == "!=" {
+		return negate(p), nil
+	}
+	return p, nil
+}
+
+// parseAmount parses an amount exactly. A leading dollar sign is allowed
+// because that's how people tend to write amounts.
+func parseAmount(value string) (*big.Rat, error) {
+	amount, ok := new(big.Rat).SetString(strings.TrimPrefix(value, "$"))
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// absoluteAmount is the size of the transaction's amount. Spending is
+// negative but people think of "spending more than $20" rather than "less
+// than -$20" so amounts are compared by size.
+func absoluteAmount(transaction upngo.TransactionResource) *big.Rat {
+	amount, ok := new(big.Rat).SetString(transaction.Attributes.Amount.Value)
+	if !ok {
+		return new(big.Rat)
+	}
+	return amount.Abs(amount)
+}
+
+func compileAmount(tok token) (predicate, error) {
+	if tok.op == ":" {
+		low, high := tok.value, tok.value
+		if parts := strings.SplitN(tok.value, "..", 2); len(parts) == 2 {
+			low, high = parts[0], parts[1]
+		}
+		lowAmount, err := parseAmount(low)
+		if err != nil {
+			return nil, err
+		}
+		highAmount, err := parseAmount(high)
+		if err != nil {
+			return nil, err
+		}
+		return func(_ Evaluator, transaction upngo.TransactionResource) bool {
+			amount := absoluteAmount(transaction)
+			return amount.Cmp(lowAmount) >= 0 && amount.Cmp(highAmount) <= 0
+		}, nil
+	}
+
+	value, err := parseAmount(tok.value)
+	if err != nil {
+		return nil, err
+	}
+	var compare func(cmp int) bool
+	switch tok.op {
+	case "=":
+		compare = func(cmp int) bool { return cmp == 0 }
+	case "!=":
+		compare = func(cmp int) bool { return cmp != 0 }
+	case ">":
+		compare = func(cmp int) bool { return cmp > 0 }
+	case ">=":
+		compare = func(cmp int) bool { return cmp >= 0 }
+	case "<":
+		compare = func(cmp int) bool { return cmp < 0 }
+	case "<=":
+		compare = func(cmp int) bool { return cmp <= 0 }
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", tok.op)
+	}
+	return func(_ Evaluator, transaction upngo.TransactionResource) bool {
+		return compare(absoluteAmount(transaction).Cmp(value))
+	}, nil
+}
+
+func compileDirection(tok token) (predicate, error) {
+	var incoming bool
+	switch strings.ToLower(tok.value) {
+	case "in":
+		incoming = true
+	case "out":
+		incoming = false
+	default:
+		return nil, fmt.Errorf("direction must be in or out, not %q", tok.value)
+	}
+
+	p := func(_ Evaluator, transaction upngo.TransactionResource) bool {
+		return (transaction.Attributes.Amount.ValueInBaseUnits > 0) == incoming
+	}
+	switch tok.op {
+	case ":", "=":
+		return p, nil
+	case "!=":
+		return negate(p), nil
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", tok.op)
+	}
+}
+
+func compileDate(tok token, now time.Time) (predicate, error) {
+	start, end, err := parsePeriod(tok.value, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var inPeriod func(date time.Time) bool
+	switch tok.op {
+	case ":", "=":
+		inPeriod = func(date time.Time) bool { return !date.Before(start) && date.Before(end) }
+	case "!=":
+		inPeriod = func(date time.Time) bool { return date.Before(start) || !date.Before(end) }
+	case ">":
+		inPeriod = func(date time.Time) bool { return !date.Before(end) }
+	case ">=":
+		inPeriod = func(date time.Time) bool { return !date.Before(start) }
+	case "<":
+		inPeriod = func(date time.Time) bool { return date.Before(start) }
+	case "<=":
+		inPeriod = func(date time.Time) bool { return date.Before(end) }
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", tok.op)
+	}
+	return func(_ Evaluator, transaction upngo.TransactionResource) bool {
+		return inPeriod(transaction.Attributes.CreatedAt)
+	}, nil
+}
+
+// equalityFields get the values of the fields that can only be compared for
+// equality. The transaction matches if any of them are equal to the term's
+// value.
+var equalityFields = map[string]func(e Evaluator, transaction upngo.TransactionResource) []string{
+	"status": func(_ Evaluator, t upngo.TransactionResource) []string {
+		return []string{string(t.Attributes.Status)}
+	},
+	"account": func(e Evaluator, t upngo.TransactionResource) []string {
+		id := t.Relationships.Account.Data.ID
+		return []string{id, e.AccountNames[id]}
+	},
+	"category": func(_ Evaluator, t upngo.TransactionResource) []string {
+		var ids []string
+		for _, category := range []upngo.OptionalRelationshipObject{t.Relationships.Category, t.Relationships.ParentCategory} {
+			if category.Data != nil {
+				ids = append(ids, category.Data.ID)
+			}
+		}
+		return ids
+	},
+	"tag": func(_ Evaluator, t upngo.TransactionResource) []string {
+		var ids []string
+		for _, tag := range t.Relationships.Tags.Data {
+			ids = append(ids, tag.ID)
+		}
+		return ids
+	},
+	"currency": func(_ Evaluator, t upngo.TransactionResource) []string {
+		return []string{t.Attributes.Amount.CurrencyCode, t.Attributes.ForeignAmount.CurrencyCode}
+	},
+	"foreign": func(_ Evaluator, t upngo.TransactionResource) []string {
+		code := t.Attributes.ForeignAmount.CurrencyCode
+		if code == "" {
+			return []string{"no"}
+		}
+		return []string{code, "yes"}
+	},
+}
+
+func compileEquality(field string, tok token) (predicate, error) {
+	get := equalityFields[field]
+	p := func(e Evaluator, transaction upngo.TransactionResource) bool {
+		for _, value := range get(e, transaction) {
+			if value != "" && strings.EqualFold(value, tok.value) {
+				return true
+			}
+		}
+		return false
+	}
+	switch tok.op {
+	case ":", "=":
+		return p, nil
+	case "!=":
+		return negate(p), nil
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", tok.op)
+	}
+}
+
+func negate(p predicate) predicate {
+	return func(e Evaluator, transaction upngo.TransactionResource) bool {
+		return !p(e, transaction)
+	}
+}
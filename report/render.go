@@ -0,0 +1,119 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/nick96/upngo"
+)
+
+// Format is a format a report can be written in.
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatCSV   Format = "csv"
+)
+
+// Write writes the report in the given format.
+func (s Spending) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatTable:
+		return s.writeTable(w)
+	case FormatJSON:
+		return s.writeJSON(w)
+	case FormatCSV:
+		return s.writeCSV(w)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func (s Spending) writeTable(w io.Writer) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 3, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(writer, "\tTransactions\tIncome (%s)\tExpenses (%s)\tNet (%s)\t\n", s.Currency, s.Currency, s.Currency)
+	rows := append(append([]Row{}, s.Rows...), s.Total)
+	for _, row := range rows {
+		fmt.Fprintf(
+			writer,
+			"%s\t%d\t%s\t%s\t%s\t\n",
+			row.Label,
+			row.Transactions,
+			upngo.FormatBaseUnits(row.Income, s.Currency),
+			upngo.FormatBaseUnits(row.Expenses, s.Currency),
+			upngo.FormatBaseUnits(row.Net(), s.Currency),
+		)
+	}
+	return writer.Flush()
+}
+
+// jsonRow is how a row is represented in JSON. Amounts are decimal strings
+// rather than numbers so they aren't mangled by anything that parses them
+// as floats.
+type jsonRow struct {
+	Key          string `json:"key"`
+	Label        string `json:"label"`
+	Transactions int    `json:"transactions"`
+	Income       string `json:"income"`
+	Expenses     string `json:"expenses"`
+	Net          string `json:"net"`
+}
+
+func (s Spending) jsonRow(row Row) jsonRow {
+	return jsonRow{
+		Key:          row.Key,
+		Label:        row.Label,
+		Transactions: row.Transactions,
+		Income:       upngo.FormatBaseUnits(row.Income, s.Currency),
+		Expenses:     upngo.FormatBaseUnits(row.Expenses, s.Currency),
+		Net:          upngo.FormatBaseUnits(row.Net(), s.Currency),
+	}
+}
+
+func (s Spending) writeJSON(w io.Writer) error {
+	output := struct {
+		By       GroupBy   `json:"by"`
+		Currency string    `json:"currency"`
+		Rows     []jsonRow `json:"rows"`
+		Total    jsonRow   `json:"total"`
+	}{
+		By:       s.By,
+		Currency: s.Currency,
+		Rows:     []jsonRow{},
+		Total:    s.jsonRow(s.Total),
+	}
+	for _, row := range s.Rows {
+		output.Rows = append(output.Rows, s.jsonRow(row))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+func (s Spending) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"key", "label", "transactions", "income", "expenses", "net", "currency"}); err != nil {
+		return err
+	}
+	for _, row := range s.Rows {
+		err := writer.Write([]string{
+			row.Key,
+			row.Label,
+			strconv.Itoa(row.Transactions),
+			upngo.FormatBaseUnits(row.Income, s.Currency),
+			upngo.FormatBaseUnits(row.Expenses, s.Currency),
+			upngo.FormatBaseUnits(row.Net(), s.Currency),
+			s.Currency,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
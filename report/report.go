@@ -0,0 +1,206 @@
+// Package report summarises transactions into reports, such as how much was
+// spent in each category.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+// GroupBy is what transactions are grouped by in a spending report.
+type GroupBy string
+
+const (
+	GroupByCategory GroupBy = "category"
+	GroupByMerchant GroupBy = "merchant"
+	GroupByAccount  GroupBy = "account"
+	GroupByDay      GroupBy = "day"
+	GroupByWeek     GroupBy = "week"
+	GroupByMonth    GroupBy = "month"
+)
+
+// GroupBys are all the ways a spending report can be grouped.
+var GroupBys = []GroupBy{
+	GroupByCategory,
+	GroupByMerchant,
+	GroupByAccount,
+	GroupByDay,
+	GroupByWeek,
+	GroupByMonth,
+}
+
+// uncategorised is the label for transactions that don't have a category.
+const uncategorised = "Uncategorised"
+
+// Options configure how a spending report is built.
+type Options struct {
+	By GroupBy
+	// ExcludeTransfers leaves out transfers between accounts.
+	ExcludeTransfers bool
+	// ExcludeRoundUps leaves out round-ups. When they're included they are
+	// counted as spending in the same group as the purchase that was
+	// rounded up.
+	ExcludeRoundUps bool
+	// AccountNames and CategoryNames map IDs to display names for labelling
+	// rows. Rows are labelled with the ID if the name isn't known.
+	AccountNames  map[string]string
+	CategoryNames map[string]string
+	// Location is the timezone used when grouping by day, week or month. It
+	// defaults to the local timezone.
+	Location *time.Location
+}
+
+// Row is the totals for one group in a spending report. Amounts are in the
+// currency's base units, e.g. cents, so arithmetic on them is exact. Expenses
+// are positive.
+type Row struct {
+	Key          string
+	Label        string
+	Transactions int
+	Income       int64
+	Expenses     int64
+}
+
+func (r *Row) add(baseUnits int64) {
+	if baseUnits >= 0 {
+		r.Income += baseUnits
+	} else {
+		r.Expenses -= baseUnits
+	}
+}
+
+// Net is the income less the expenses.
+func (r Row) Net() int64 {
+	return r.Income - r.Expenses
+}
+
+// Spending is a report of income and expenses grouped by category, merchant,
+// account or period.
+type Spending struct {
+	By       GroupBy
+	Currency string
+	Rows     []Row
+	Total    Row
+}
+
+// NewSpending builds a spending report from the transactions. Rows grouped by
+// period are in chronological order, everything else is ordered by the largest
+// expenses first.
+func NewSpending(transactions []upngo.TransactionResource, options Options) (Spending, error) {
+	if options.Location == nil {
+		options.Location = time.Local
+	}
+	keyFn, err := options.keyFunc()
+	if err != nil {
+		return Spending{}, err
+	}
+
+	report := Spending{
+		By:    options.By,
+		Total: Row{Key: "total", Label: "Total"},
+	}
+	rows := make(map[string]*Row)
+	for _, transaction := range transactions {
+		if options.ExcludeTransfers && transaction.Relationships.TransferAccount.Data != nil {
+			continue
+		}
+
+		key, label := keyFn(transaction)
+		row, ok := rows[key]
+		if !ok {
+			row = &Row{Key: key, Label: label}
+			rows[key] = row
+		}
+		row.Transactions++
+		report.Total.Transactions++
+
+		amounts := []upngo.MoneyObject{transaction.Attributes.Amount}
+		if roundUp := transaction.Attributes.RoundUp.Amount; !options.ExcludeRoundUps && roundUp.ValueInBaseUnits != 0 {
+			amounts = append(amounts, roundUp)
+		}
+		for _, amount := range amounts {
+			if report.Currency == "" {
+				report.Currency = amount.CurrencyCode
+			} else if amount.CurrencyCode != report.Currency {
+				return Spending{}, fmt.Errorf(
+					"transaction %s is in %s but the report is in %s",
+					transaction.ID,
+					amount.CurrencyCode,
+					report.Currency,
+				)
+			}
+			row.add(amount.ValueInBaseUnits)
+			report.Total.add(amount.ValueInBaseUnits)
+		}
+	}
+
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if options.isPeriod() || a.Expenses == b.Expenses {
+			return a.Key < b.Key
+		}
+		return a.Expenses > b.Expenses
+	})
+	return report, nil
+}
+
+func (o Options) isPeriod() bool {
+	return o.By == GroupByDay || o.By == GroupByWeek || o.By == GroupByMonth
+}
+
+// keyFunc gets the function that works out the key and label of the row a
+// transaction belongs in. Period keys sort chronologically.
+func (o Options) keyFunc() (func(upngo.TransactionResource) (string, string), error) {
+	switch o.By {
+	case GroupByCategory:
+		return func(transaction upngo.TransactionResource) (string, string) {
+			category := transaction.Relationships.Category.Data
+			if category == nil {
+				return "", uncategorised
+			}
+			return category.ID, lookup(o.CategoryNames, category.ID)
+		}, nil
+	case GroupByMerchant:
+		return func(transaction upngo.TransactionResource) (string, string) {
+			return transaction.Attributes.Description, transaction.Attributes.Description
+		}, nil
+	case GroupByAccount:
+		return func(transaction upngo.TransactionResource) (string, string) {
+			id := transaction.Relationships.Account.Data.ID
+			return id, lookup(o.AccountNames, id)
+		}, nil
+	case GroupByDay:
+		return func(transaction upngo.TransactionResource) (string, string) {
+			date := transaction.Attributes.CreatedAt.In(o.Location)
+			key := date.Format("2006-01-02")
+			return key, key
+		}, nil
+	case GroupByWeek:
+		return func(transaction upngo.TransactionResource) (string, string) {
+			date := transaction.Attributes.CreatedAt.In(o.Location)
+			// Weeks start on Monday.
+			start := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+			key := start.Format("2006-01-02")
+			return key, "Week of " + key
+		}, nil
+	case GroupByMonth:
+		return func(transaction upngo.TransactionResource) (string, string) {
+			date := transaction.Attributes.CreatedAt.In(o.Location)
+			return date.Format("2006-01"), date.Format("January 2006")
+		}, nil
+	}
+	return nil, fmt.Errorf("can't group by %q", o.By)
+}
+
+func lookup(names map[string]string, id string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return id
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+func newTransaction(id, description, category string, baseUnits int64, createdAt time.Time) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.ID = id
+	transaction.Attributes.Description = description
+	transaction.Attributes.Amount = upngo.MoneyObject{CurrencyCode: "AUD", ValueInBaseUnits: baseUnits}
+	transaction.Attributes.CreatedAt = createdAt
+	transaction.Relationships.Account.Data.ID = "spending"
+	if category != "" {
+		transaction.Relationships.Category.Data = &upngo.DataObject{Type: "categories", ID: category}
+	}
+	return transaction
+}
+
+func testTransactions() []upngo.TransactionResource {
+	coffee := newTransaction("1", "Coffee", "restaurants-and-cafes", -450, time.Date(2020, 8, 3, 9, 0, 0, 0, time.UTC))
+	coffee.Attributes.RoundUp.Amount = upngo.MoneyObject{CurrencyCode: "AUD", ValueInBaseUnits: -50}
+	moreCoffee := newTransaction("2", "Coffee", "restaurants-and-cafes", -500, time.Date(2020, 8, 10, 9, 0, 0, 0, time.UTC))
+	groceries := newTransaction("3", "Woolies", "groceries", -10001, time.Date(2020, 8, 9, 9, 0, 0, 0, time.UTC))
+	pay := newTransaction("4", "Pay", "", 100000, time.Date(2020, 9, 1, 9, 0, 0, 0, time.UTC))
+	transfer := newTransaction("5", "Transfer to Savings", "", -2000, time.Date(2020, 9, 1, 9, 0, 0, 0, time.UTC))
+	transfer.Relationships.TransferAccount.Data = &upngo.DataObject{Type: "accounts", ID: "saver"}
+	return []upngo.TransactionResource{coffee, moreCoffee, groceries, pay, transfer}
+}
+
+func TestSpendingByCategory(t *testing.T) {
+	report, err := NewSpending(testTransactions(), Options{
+		By:               GroupByCategory,
+		ExcludeTransfers: true,
+		CategoryNames:    map[string]string{"groceries": "Groceries"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "AUD", report.Currency)
+	require.Equal(t, []Row{
+		{Key: "groceries", Label: "Groceries", Transactions: 1, Expenses: 10001},
+		{Key: "restaurants-and-cafes", Label: "restaurants-and-cafes", Transactions: 2, Expenses: 1000},
+		{Key: "", Label: "Uncategorised", Transactions: 1, Income: 100000},
+	}, report.Rows)
+	require.Equal(t, Row{Key: "total", Label: "Total", Transactions: 4, Income: 100000, Expenses: 11001}, report.Total)
+	require.Equal(t, int64(88999), report.Total.Net())
+}
+
+func TestSpendingByWeek(t *testing.T) {
+	report, err := NewSpending(testTransactions(), Options{
+		By:              GroupByWeek,
+		ExcludeRoundUps: true,
+		Location:        time.UTC,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []Row{
+		{Key: "2020-08-03", Label: "Week of 2020-08-03", Transactions: 2, Expenses: 10451},
+		{Key: "2020-08-10", Label: "Week of 2020-08-10", Transactions: 1, Expenses: 500},
+		{Key: "2020-08-31", Label: "Week of 2020-08-31", Transactions: 2, Income: 100000, Expenses: 2000},
+	}, report.Rows)
+}
+
+func TestSpendingMixedCurrencies(t *testing.T) {
+	transactions := testTransactions()
+	transactions[1].Attributes.Amount.CurrencyCode = "USD"
+	_, err := NewSpending(transactions, Options{By: GroupByMerchant})
+	require.Error(t, err)
+}
+
+func TestWriteCSV(t *testing.T) {
+	report, err := NewSpending(testTransactions(), Options{By: GroupByMonth, Location: time.UTC})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, FormatCSV))
+	expected := `key,label,transactions,income,expenses,net,currency
+2020-08,August 2020,3,0.00,110.01,-110.01,AUD
+2020-09,September 2020,2,1000.00,20.00,980.00,AUD
+`
+	require.Equal(t, expected, buf.String())
+}
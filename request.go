@@ -0,0 +1,129 @@
+package upngo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Option is an option for an endpoint. Most are URL params but some, like a
+// webhook's description, go in the request body instead.
+//
+// Each endpoint takes its own kind of option, like AccountsOption or
+// RegisterWebhookOption, so options can only be given to the endpoints that
+// understand them. They're built with constructors like WithPageSize.
+type Option interface {
+	// Name is the option's name, e.g. the key of the URL param it sets.
+	Name() string
+	// Value is the option's value, already formatted as a string.
+	Value() string
+}
+
+// option is the name and value that every kind of option is made of.
+//
+// Once it gets to the point that this struct is constructed, the type of value
+// doesn't really matter because it's just going into the URL param so it's a
+// string for convenience. It's the job of the constructor to convert the given
+// value to a string.
+//
+// For constructors, the `name` should be the key in the URL param and `value`
+// should be the value, i.e. options will be formatted as `<name>=<value>` in
+// the URL.
+type option struct {
+	name  string
+	value string
+}
+
+func (o option) Name() string {
+	return o.name
+}
+
+func (o option) Value() string {
+	return o.value
+}
+
+// request describes a request to the API for `do` to send.
+type request struct {
+	// name describes the request in errors, e.g. "get accounts".
+	name   string
+	method string
+	// url is the full URL, either built with `buildURL` or a link from a
+	// previous response.
+	url string
+	// options are added to the URL params. Options that are already in the
+	// URL, like those in a next page link, are kept.
+	options []Option
+	// body is marshalled to JSON and sent as the request body if it isn't
+	// nil.
+	body interface{}
+	// status is the status code the API responds with when the request
+	// succeeds. It defaults to 200.
+	status int
+}
+
+// do sends the request and unmarshals the response into `out`, unless it's
+// nil. If the API responds with anything but the expected status then the
+// errors in the response are returned.
+func (c *Client) do(r request, out interface{}) error {
+	var body io.Reader
+	if r.body != nil {
+		requestBody, err := json.Marshal(r.body)
+		if err != nil {
+			// Something's really goofed up here.
+			return fmt.Errorf("failed to marshal %s request body (this should never happen): %w", r.name, err)
+		}
+		body = bytes.NewReader(requestBody)
+	}
+
+	req, err := c.newRequest(r.method, r.url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", r.name, err)
+	}
+	if r.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if len(r.options) > 0 {
+		query := req.URL.Query()
+		for _, option := range r.options {
+			// Using `Add`, not `Set` is important because it means that if
+			// an option is supplied twice then both values are included in
+			// the query.
+			query.Add(option.Name(), option.Value())
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s request: %w", r.name, err)
+	}
+	defer resp.Body.Close()
+
+	// Read the body out here because in either case (error or happy), we're
+	// going to want the body. The only difference will be in the structure
+	// we parse the JSON into.
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", r.name, err)
+	}
+
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if resp.StatusCode != status {
+		return unmarshalToErr(responseBody)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := unmarshal(responseBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal %s response: %w", r.name, err)
+	}
+	return nil
+}
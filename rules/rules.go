@@ -0,0 +1,254 @@
+// Package rules automatically tags and categorises transactions based on a
+// set of declarative rules.
+package rules
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/webhook"
+	"github.com/spf13/viper"
+)
+
+// Match is the conditions a transaction has to meet for a rule to apply. Every
+// condition that is set has to match. Regular expressions are case
+// insensitive and amounts are compared by their size, so 20 matches both $20
+// spent and $20 received.
+type Match struct {
+	Description     string `mapstructure:"description"`
+	RawText         string `mapstructure:"rawtext"`
+	MinAmount       string `mapstructure:"min-amount"`
+	MaxAmount       string `mapstructure:"max-amount"`
+	Account         string `mapstructure:"account"`
+	ForeignCurrency string `mapstructure:"foreign-currency"`
+}
+
+// Rule adds tags to and/or sets the category of matching transactions.
+type Rule struct {
+	Name     string   `mapstructure:"name"`
+	Match    Match    `mapstructure:"match"`
+	Tags     []string `mapstructure:"tags"`
+	Category string   `mapstructure:"category"`
+}
+
+// Client is the part of upngo.TransactionsAPI needed to apply rules, e.g. a
+// client's Transactions service.
+type Client interface {
+	Get(id string) (upngo.TransactionResponse, error)
+	Categorize(transactionID string, categoryID string) error
+	Tag(transactionID string, tags ...string) error
+}
+
+type compiledRule struct {
+	Rule
+	description *regexp.Regexp
+	rawText     *regexp.Regexp
+	minAmount   *big.Rat
+	maxAmount   *big.Rat
+}
+
+func compileRegex(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return regexp.Compile("(?i)" + expr)
+}
+
+func parseAmount(value string) (*big.Rat, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+func compile(rule Rule) (compiledRule, error) {
+	compiled := compiledRule{Rule: rule}
+	if len(rule.Tags) == 0 && rule.Category == "" {
+		return compiled, fmt.Errorf("rule doesn't add any tags or set a category")
+	}
+
+	var err error
+	if compiled.description, err = compileRegex(rule.Match.Description); err != nil {
+		return compiled, fmt.Errorf("invalid description regex: %w", err)
+	}
+	if compiled.rawText, err = compileRegex(rule.Match.RawText); err != nil {
+		return compiled, fmt.Errorf("invalid rawtext regex: %w", err)
+	}
+	if compiled.minAmount, err = parseAmount(rule.Match.MinAmount); err != nil {
+		return compiled, err
+	}
+	if compiled.maxAmount, err = parseAmount(rule.Match.MaxAmount); err != nil {
+		return compiled, err
+	}
+	return compiled, nil
+}
+
+func (r compiledRule) matches(transaction upngo.TransactionResource) bool {
+	attributes := transaction.Attributes
+	if r.description != nil && !r.description.MatchString(attributes.Description) {
+		return false
+	}
+	if r.rawText != nil && !r.rawText.MatchString(attributes.RawText) {
+		return false
+	}
+	if r.Match.Account != "" && transaction.Relationships.Account.Data.ID != r.Match.Account {
+		return false
+	}
+	if r.Match.ForeignCurrency != "" && !strings.EqualFold(attributes.ForeignAmount.CurrencyCode, r.Match.ForeignCurrency) {
+		return false
+	}
+
+	if r.minAmount != nil || r.maxAmount != nil {
+		amount, ok := new(big.Rat).SetString(attributes.Amount.Value)
+		if !ok {
+			return false
+		}
+		amount.Abs(amount)
+		if r.minAmount != nil && amount.Cmp(r.minAmount) < 0 {
+			return false
+		}
+		if r.maxAmount != nil && amount.Cmp(r.maxAmount) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Engine applies rules to transactions.
+type Engine struct {
+	rules []compiledRule
+}
+
+// New creates an engine for the rules.
+func New(rules []Rule) (*Engine, error) {
+	engine := &Engine{}
+	for i, rule := range rules {
+		compiled, err := compile(rule)
+		if err != nil {
+			name := rule.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("invalid rule %s: %w", name, err)
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+	return engine, nil
+}
+
+// Load creates an engine from the rules in the file at `path`. The file can be
+// in any format viper understands, with the rules in a top level `rules` list.
+func Load(path string) (*Engine, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read rules file %s: %w", path, err)
+	}
+
+	var rules []Rule
+	if err := v.UnmarshalKey("rules", &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", path, err)
+	}
+	return New(rules)
+}
+
+// Change is what applying the rules will change about a transaction.
+type Change struct {
+	Transaction upngo.TransactionResource
+	// Rules are the names of the rules that matched.
+	Rules []string
+	// Tags are the tags to add. Tags the transaction already has aren't
+	// included.
+	Tags []string
+	// Category is the category to set, or empty if it doesn't need to
+	// change.
+	Category string
+}
+
+// Empty reports whether the change doesn't actually change anything.
+func (c Change) Empty() bool {
+	return len(c.Tags) == 0 && c.Category == ""
+}
+
+// Plan works out what the rules would change about the transaction. Tags from
+// every matching rule are added, but only the first matching rule with a
+// category gets to set it.
+func (e *Engine) Plan(transaction upngo.TransactionResource) Change {
+	change := Change{Transaction: transaction}
+
+	existing := make(map[string]bool)
+	for _, tag := range transaction.Relationships.Tags.Data {
+		existing[tag.ID] = true
+	}
+	category := ""
+	if current := transaction.Relationships.Category.Data; current != nil {
+		category = current.ID
+	}
+
+	categorySet := false
+	for _, rule := range e.rules {
+		if !rule.matches(transaction) {
+			continue
+		}
+		change.Rules = append(change.Rules, rule.Name)
+		for _, tag := range rule.Tags {
+			if !existing[tag] {
+				existing[tag] = true
+				change.Tags = append(change.Tags, tag)
+			}
+		}
+		if rule.Category != "" && !categorySet {
+			categorySet = true
+			if rule.Category != category {
+				change.Category = rule.Category
+			}
+		}
+	}
+	return change
+}
+
+// Apply makes the change to the transaction through the API.
+func Apply(client Client, change Change) error {
+	id := change.Transaction.ID
+	if change.Category != "" {
+		if err := client.Categorize(id, change.Category); err != nil {
+			return err
+		}
+	}
+	if len(change.Tags) > 0 {
+		if err := client.Tag(id, change.Tags...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WebhookHandler is a webhook handler that applies the rules to transactions as
+// they're created. Register it with a webhook.Receiver for
+// upngo.WebhookEventTypeTransactionCreated events.
+func (e *Engine) WebhookHandler(client Client) webhook.Handler {
+	return webhook.HandlerFunc(func(event webhook.Event) error {
+		if event.Data.Attributes.EventType != upngo.WebhookEventTypeTransactionCreated {
+			return nil
+		}
+
+		id := event.Data.Relationships.Transaction.Data.ID
+		transaction, err := client.Get(id)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction %s: %w", id, err)
+		}
+
+		change := e.Plan(transaction.Data)
+		if change.Empty() {
+			return nil
+		}
+		return Apply(client, change)
+	})
+}
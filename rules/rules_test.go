@@ -0,0 +1,165 @@
+package rules
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient records the changes made to transactions instead of sending
+// them to the API.
+type fakeClient struct {
+	transactions map[string]upngo.TransactionResource
+	categories   map[string]string
+	tags         map[string][]string
+}
+
+func newFakeClient(transactions ...upngo.TransactionResource) *fakeClient {
+	client := &fakeClient{
+		transactions: make(map[string]upngo.TransactionResource),
+		categories:   make(map[string]string),
+		tags:         make(map[string][]string),
+	}
+	for _, transaction := range transactions {
+		client.transactions[transaction.ID] = transaction
+	}
+	return client
+}
+
+func (c *fakeClient) Get(id string) (upngo.TransactionResponse, error) {
+	return upngo.TransactionResponse{Data: c.transactions[id]}, nil
+}
+
+func (c *fakeClient) Categorize(transactionID string, categoryID string) error {
+	c.categories[transactionID] = categoryID
+	return nil
+}
+
+func (c *fakeClient) Tag(transactionID string, tags ...string) error {
+	c.tags[transactionID] = append(c.tags[transactionID], tags...)
+	return nil
+}
+
+func newTransaction(id, description, amount string) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.ID = id
+	transaction.Attributes.Description = description
+	transaction.Attributes.RawText = description
+	transaction.Attributes.Amount = upngo.MoneyObject{CurrencyCode: "AUD", Value: amount}
+	transaction.Relationships.Account.Data.ID = "spending"
+	return transaction
+}
+
+var testRules = []Rule{
+	{
+		Name:     "uber",
+		Match:    Match{Description: "^uber$", MinAmount: "20"},
+		Tags:     []string{"Transport", "Expensive"},
+		Category: "taxis-and-share-cars",
+	},
+	{
+		Name:  "all uber",
+		Match: Match{Description: "^uber"},
+		Tags:  []string{"Transport"},
+	},
+	{
+		Name:  "overseas",
+		Match: Match{ForeignCurrency: "usd", Account: "spending"},
+		Tags:  []string{"Overseas"},
+	},
+}
+
+func TestPlan(t *testing.T) {
+	engine, err := New(testRules)
+	require.NoError(t, err)
+
+	expensive := newTransaction("1", "Uber", "-25.00")
+	change := engine.Plan(expensive)
+	require.Equal(t, []string{"uber", "all uber"}, change.Rules)
+	require.Equal(t, []string{"Transport", "Expensive"}, change.Tags)
+	require.Equal(t, "taxis-and-share-cars", change.Category)
+
+	cheap := newTransaction("2", "UBER EATS", "-10.00")
+	cheap.Relationships.Tags.Data = []upngo.DataObject{{Type: "tags", ID: "Transport"}}
+	change = engine.Plan(cheap)
+	require.Equal(t, []string{"all uber"}, change.Rules)
+	require.True(t, change.Empty())
+
+	hotel := newTransaction("3", "Hotel", "-150.00")
+	hotel.Attributes.ForeignAmount = upngo.MoneyObject{CurrencyCode: "USD", Value: "-100.00"}
+	change = engine.Plan(hotel)
+	require.Equal(t, []string{"Overseas"}, change.Tags)
+	require.Empty(t, change.Category)
+}
+
+func TestNewInvalid(t *testing.T) {
+	_, err := New([]Rule{{Name: "nothing", Match: Match{Description: "x"}}})
+	require.Error(t, err)
+	_, err = New([]Rule{{Match: Match{Description: "("}, Tags: []string{"x"}}})
+	require.Error(t, err)
+	_, err = New([]Rule{{Match: Match{MinAmount: "lots"}, Tags: []string{"x"}}})
+	require.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "upngo-rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "rules.yaml")
+	err = ioutil.WriteFile(path, []byte(`
+rules:
+  - name: uber
+    match:
+      description: ^uber$
+      min-amount: 20
+    tags: [Transport]
+    category: taxis-and-share-cars
+`), 0600)
+	require.NoError(t, err)
+
+	engine, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, engine.rules, 1)
+	require.Equal(t, "20", engine.rules[0].Match.MinAmount)
+	require.Equal(t, "taxis-and-share-cars", engine.Plan(newTransaction("1", "uber", "-20")).Category)
+}
+
+func TestWebhookHandler(t *testing.T) {
+	engine, err := New(testRules)
+	require.NoError(t, err)
+	client := newFakeClient(newTransaction("1", "Uber", "-25.00"))
+
+	secretKey := "secret"
+	receiver := webhook.NewReceiver(secretKey)
+	receiver.Handle(upngo.WebhookEventTypeTransactionCreated, engine.WebhookHandler(client))
+
+	var event webhook.Event
+	event.Data.Attributes.EventType = upngo.WebhookEventTypeTransactionCreated
+	event.Data.Relationships.Transaction.Data.ID = "1"
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write(body)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set(webhook.SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "taxis-and-share-cars", client.categories["1"])
+	require.Equal(t, []string{"Transport", "Expensive"}, client.tags["1"])
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nick96/upngo/networth"
+	bolt "go.etcd.io/bbolt"
+)
+
+// balance is how a day's balance is stored. The account and day are in the
+// key.
+type balance struct {
+	Currency string `json:"currency"`
+	Balance  int64  `json:"balance"`
+}
+
+// balanceKey is the key of an account's balance on `day`. The day is
+// formatted so keys sort by day within each account.
+func balanceKey(accountID string, day time.Time) []byte {
+	return []byte(accountID + "/" + day.Format("2006-01-02"))
+}
+
+// SaveBalances stores the daily balances in `series`, replacing any that are
+// already stored for the same account and day.
+func (s *Store) SaveBalances(series networth.Series) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(balancesBucket)
+		for _, point := range series.Points {
+			data, err := json.Marshal(balance{Currency: series.Currency, Balance: point.Balance})
+			if err != nil {
+				return err
+			}
+			if err := b.Put(balanceKey(series.AccountID, point.Date), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store balances of account %s: %w", series.AccountID, err)
+	}
+	return nil
+}
+
+// Balances gets the stored daily balances of the account with the given ID on
+// and after `since`, oldest first. A zero `since` gets all of them.
+func (s *Store) Balances(accountID string, since time.Time) (networth.Series, error) {
+	series := networth.Series{AccountID: accountID}
+	prefix := accountID + "/"
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(balancesBucket).Cursor()
+		start := []byte(prefix)
+		if !since.IsZero() {
+			start = balanceKey(accountID, since)
+		}
+		for key, data := c.Seek(start); key != nil && strings.HasPrefix(string(key), prefix); key, data = c.Next() {
+			day, err := time.Parse("2006-01-02", strings.TrimPrefix(string(key), prefix))
+			if err != nil {
+				return fmt.Errorf("invalid balance key %s: %w", key, err)
+			}
+			var value balance
+			if err := json.Unmarshal(data, &value); err != nil {
+				return err
+			}
+			series.Currency = value.Currency
+			series.Points = append(series.Points, networth.Point{Date: day, Balance: value.Balance})
+		}
+		return nil
+	})
+	if err != nil {
+		return networth.Series{}, fmt.Errorf("failed to read balances of account %s from store: %w", accountID, err)
+	}
+	return series, nil
+}
+
+// SnapshotBalances works out the daily balances of each stored account from
+// `since` to `now`, in `loc`, from its current balance and stored transactions
+// and stores them. If `since` is zero, or an account doesn't have any balances
+// stored yet, its whole history is worked out, which needs every transaction
+// to have been synced.
+func (s *Store) SnapshotBalances(since, now time.Time, loc *time.Location) error {
+	accounts, err := s.Accounts()
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		from := since
+		existing, err := s.Balances(account.ID, time.Time{})
+		if err != nil {
+			return err
+		}
+		if from.IsZero() || len(existing.Points) == 0 {
+			from, err = s.oldestTransaction(account.ID, account.Attributes.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+		if from.IsZero() {
+			from = now
+		}
+
+		transactions, err := s.Transactions(TransactionFilter{AccountID: account.ID, Since: from})
+		if err != nil {
+			return err
+		}
+		if err := s.SaveBalances(networth.History(account, transactions, from, now, loc)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// oldestTransaction is when the oldest stored transaction in the account was
+// created, or `created` if that's earlier.
+func (s *Store) oldestTransaction(accountID string, created time.Time) (time.Time, error) {
+	transactions, err := s.Transactions(TransactionFilter{AccountID: accountID})
+	if err != nil {
+		return time.Time{}, err
+	}
+	oldest := created
+	if len(transactions) > 0 {
+		last := transactions[len(transactions)-1].Attributes.CreatedAt
+		if oldest.IsZero() || last.Before(oldest) {
+			oldest = last
+		}
+	}
+	return oldest, nil
+}
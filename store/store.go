@@ -0,0 +1,305 @@
+// Package store persists UpBank resources locally so they can be used without
+// hitting the API every time.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/nick96/upngo"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	accountsBucket     = []byte("accounts")
+	transactionsBucket = []byte("transactions")
+	categoriesBucket   = []byte("categories")
+	tagsBucket         = []byte("tags")
+	metaBucket         = []byte("meta")
+	balancesBucket     = []byte("balances")
+
+	highWaterMarkKey = []byte("high-water-mark")
+)
+
+// ErrNotFound is returned when a resource isn't in the store.
+var ErrNotFound = errors.New("not found in store")
+
+// Store is a local copy of UpBank resources backed by an embedded database.
+type Store struct {
+	db *bolt.DB
+}
+
+// DefaultName is the file name of the store at DefaultPath.
+const DefaultName = "upngo.db"
+
+// DefaultPath is where the store lives if no other path is given. It respects
+// `XDG_DATA_HOME`, falling back to `~/.local/share`.
+func DefaultPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find home directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "upngo", DefaultName), nil
+}
+
+// Open opens the store at `path`, creating it if it doesn't exist.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	// The timeout stops us from hanging forever if another upngo process has
+	// the database open.
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store at %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{accountsBucket, transactionsBucket, categoriesBucket, tagsBucket, metaBucket, balancesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return fmt.Errorf("failed to create %s bucket: %w", bucket, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the store.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// get unmarshals the value at `key` in `bucket` into `v`.
+func (s *Store) get(bucket []byte, key string, v interface{}) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(bucket).Get([]byte(key))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, v)
+	})
+}
+
+// forEach calls `fn` with every value in `bucket`.
+func (s *Store) forEach(bucket []byte, fn func(data []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(_, data []byte) error {
+			return fn(data)
+		})
+	})
+}
+
+// replace replaces everything in `bucket` with `values`, which is keyed by ID.
+func (s *Store) replace(bucket []byte, values map[string]interface{}) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(bucket); err != nil {
+			return err
+		}
+		b, err := tx.CreateBucket(bucket)
+		if err != nil {
+			return err
+		}
+		for id, value := range values {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("failed to marshal %s: %w", id, err)
+			}
+			if err := b.Put([]byte(id), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Accounts gets all the stored accounts, oldest first.
+func (s *Store) Accounts() ([]upngo.AccountResource, error) {
+	var accounts []upngo.AccountResource
+	err := s.forEach(accountsBucket, func(data []byte) error {
+		var account upngo.AccountResource
+		if err := json.Unmarshal(data, &account); err != nil {
+			return err
+		}
+		accounts = append(accounts, account)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accounts from store: %w", err)
+	}
+
+	sort.SliceStable(accounts, func(i, j int) bool {
+		return accounts[i].Attributes.CreatedAt.Before(accounts[j].Attributes.CreatedAt)
+	})
+	return accounts, nil
+}
+
+// Account gets the stored account with the given ID.
+func (s *Store) Account(id string) (upngo.AccountResource, error) {
+	var account upngo.AccountResource
+	if err := s.get(accountsBucket, id, &account); err != nil {
+		return upngo.AccountResource{}, fmt.Errorf("failed to get account %s from store: %w", id, err)
+	}
+	return account, nil
+}
+
+// ReplaceAccounts replaces all the stored accounts.
+func (s *Store) ReplaceAccounts(accounts []upngo.AccountResource) error {
+	values := make(map[string]interface{}, len(accounts))
+	for _, account := range accounts {
+		values[account.ID] = account
+	}
+	if err := s.replace(accountsBucket, values); err != nil {
+		return fmt.Errorf("failed to store accounts: %w", err)
+	}
+	return nil
+}
+
+// TransactionFilter narrows down the transactions returned from the store.
+// The zero value matches everything.
+type TransactionFilter struct {
+	// AccountID only matches transactions in the account with this ID.
+	AccountID string
+	// Since only matches transactions created at or after this time.
+	Since time.Time
+	// Until only matches transactions created before this time.
+	Until time.Time
+}
+
+func (f TransactionFilter) matches(transaction upngo.TransactionResource) bool {
+	createdAt := transaction.Attributes.CreatedAt
+	if f.AccountID != "" && transaction.Relationships.Account.Data.ID != f.AccountID {
+		return false
+	}
+	if !f.Since.IsZero() && createdAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !createdAt.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Transactions gets the stored transactions that match the filter, newest
+// first like the API.
+func (s *Store) Transactions(filter TransactionFilter) ([]upngo.TransactionResource, error) {
+	var transactions []upngo.TransactionResource
+	err := s.forEach(transactionsBucket, func(data []byte) error {
+		var transaction upngo.TransactionResource
+		if err := json.Unmarshal(data, &transaction); err != nil {
+			return err
+		}
+		if filter.matches(transaction) {
+			transactions = append(transactions, transaction)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transactions from store: %w", err)
+	}
+
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Attributes.CreatedAt.After(transactions[j].Attributes.CreatedAt)
+	})
+	return transactions, nil
+}
+
+// Transaction gets the stored transaction with the given ID.
+func (s *Store) Transaction(id string) (upngo.TransactionResource, error) {
+	var transaction upngo.TransactionResource
+	if err := s.get(transactionsBucket, id, &transaction); err != nil {
+		return upngo.TransactionResource{}, fmt.Errorf("failed to get transaction %s from store: %w", id, err)
+	}
+	return transaction, nil
+}
+
+// Categories gets all the stored categories, sorted by ID.
+func (s *Store) Categories() ([]upngo.CategoryResource, error) {
+	var categories []upngo.CategoryResource
+	err := s.forEach(categoriesBucket, func(data []byte) error {
+		var category upngo.CategoryResource
+		if err := json.Unmarshal(data, &category); err != nil {
+			return err
+		}
+		categories = append(categories, category)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read categories from store: %w", err)
+	}
+	return categories, nil
+}
+
+// ReplaceCategories replaces all the stored categories.
+func (s *Store) ReplaceCategories(categories []upngo.CategoryResource) error {
+	values := make(map[string]interface{}, len(categories))
+	for _, category := range categories {
+		values[category.ID] = category
+	}
+	if err := s.replace(categoriesBucket, values); err != nil {
+		return fmt.Errorf("failed to store categories: %w", err)
+	}
+	return nil
+}
+
+// Tags gets all the stored tags, sorted by label.
+func (s *Store) Tags() ([]upngo.TagResource, error) {
+	var tags []upngo.TagResource
+	err := s.forEach(tagsBucket, func(data []byte) error {
+		var tag upngo.TagResource
+		if err := json.Unmarshal(data, &tag); err != nil {
+			return err
+		}
+		tags = append(tags, tag)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags from store: %w", err)
+	}
+	return tags, nil
+}
+
+// ReplaceTags replaces all the stored tags.
+func (s *Store) ReplaceTags(tags []upngo.TagResource) error {
+	values := make(map[string]interface{}, len(tags))
+	for _, tag := range tags {
+		values[tag.ID] = tag
+	}
+	if err := s.replace(tagsBucket, values); err != nil {
+		return fmt.Errorf("failed to store tags: %w", err)
+	}
+	return nil
+}
+
+// HighWaterMark is the time of the last successful sync. It is the zero time if
+// the store has never been synced.
+func (s *Store) HighWaterMark() (time.Time, error) {
+	var mark time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(metaBucket).Get(highWaterMarkKey)
+		if data == nil {
+			return nil
+		}
+		return mark.UnmarshalText(data)
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read high water mark from store: %w", err)
+	}
+	return mark, nil
+}
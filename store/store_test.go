@@ -0,0 +1,190 @@
+package store
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/nick96/upngo/mock"
+	"github.com/nick96/upngo/networth"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestStore opens a store in a temporary directory. The returned function
+// closes the store and cleans up the directory.
+func openTestStore(t *testing.T) (*Store, func()) {
+	dir, err := ioutil.TempDir("", "upngo-store")
+	require.NoError(t, err)
+
+	s, err := Open(filepath.Join(dir, "upngo.db"))
+	require.NoError(t, err)
+	return s, func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func newTransaction(id, accountID string, status upngo.TransactionStatus, createdAt time.Time) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.ID = id
+	transaction.Attributes.Status = status
+	transaction.Attributes.CreatedAt = createdAt
+	transaction.Relationships.Account.Data.ID = accountID
+	return transaction
+}
+
+func TestAccounts(t *testing.T) {
+	s, cleanup := openTestStore(t)
+	defer cleanup()
+
+	var older, newer upngo.AccountResource
+	older.ID = "b"
+	older.Attributes.CreatedAt = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer.ID = "a"
+	newer.Attributes.CreatedAt = time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.ReplaceAccounts([]upngo.AccountResource{newer, older}))
+
+	accounts, err := s.Accounts()
+	require.NoError(t, err)
+	require.Equal(t, []upngo.AccountResource{older, newer}, accounts)
+
+	account, err := s.Account("a")
+	require.NoError(t, err)
+	require.Equal(t, newer, account)
+
+	_, err = s.Account("missing")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	require.NoError(t, s.ReplaceAccounts([]upngo.AccountResource{older}))
+	accounts, err = s.Accounts()
+	require.NoError(t, err)
+	require.Equal(t, []upngo.AccountResource{older}, accounts)
+}
+
+func TestReconcileTransactions(t *testing.T) {
+	s, cleanup := openTestStore(t)
+	defer cleanup()
+
+	day := func(d int) time.Time { return time.Date(2020, 8, d, 0, 0, 0, 0, time.UTC) }
+	old := newTransaction("old", "account", upngo.TransactionStatusSettled, day(1))
+	held := newTransaction("held", "account", upngo.TransactionStatusHeld, day(2))
+	deleted := newTransaction("deleted", "account", upngo.TransactionStatusHeld, day(3))
+
+	var result SyncResult
+	require.NoError(t, s.reconcileTransactions([]upngo.TransactionResource{old, held, deleted}, time.Time{}, day(4), &result))
+	require.Equal(t, SyncResult{Added: 3}, result)
+
+	mark, err := s.HighWaterMark()
+	require.NoError(t, err)
+	require.True(t, mark.Equal(day(4)))
+
+	// The oldest held transaction is before the overlap so the next sync
+	// needs to go back to it.
+	since, err := s.syncWindowStart()
+	require.NoError(t, err)
+	require.Equal(t, day(2), since)
+
+	settled := held
+	settled.Attributes.Status = upngo.TransactionStatusSettled
+	added := newTransaction("added", "other", upngo.TransactionStatusSettled, day(4))
+
+	result = SyncResult{}
+	require.NoError(t, s.reconcileTransactions([]upngo.TransactionResource{settled, added}, since, day(5), &result))
+	require.Equal(t, SyncResult{Added: 1, Updated: 1, Deleted: 1}, result)
+
+	transactions, err := s.Transactions(TransactionFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []upngo.TransactionResource{added, settled, old}, transactions)
+
+	transactions, err = s.Transactions(TransactionFilter{AccountID: "account", Since: day(2)})
+	require.NoError(t, err)
+	require.Equal(t, []upngo.TransactionResource{settled}, transactions)
+}
+
+func TestSync(t *testing.T) {
+	s, cleanup := openTestStore(t)
+	defer cleanup()
+
+	bank := mock.New()
+	var account upngo.AccountResource
+	account.ID = "spending"
+	bank.AddAccounts(account)
+	bank.AddTransactions(newTransaction("1", "spending", upngo.TransactionStatusSettled, time.Now().Add(-time.Hour)))
+	require.NoError(t, bank.Transactions.Tag("1", "Holiday"))
+
+	result, err := Sync(bank.Client(), s, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Accounts)
+	require.Equal(t, 1, result.Tags)
+	require.Equal(t, 1, result.Added)
+
+	transactions, err := s.Transactions(TransactionFilter{})
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+
+	bank.FailWith(errors.New("boom"))
+	_, err = Sync(bank.Client(), s, false)
+	require.Error(t, err)
+}
+
+func TestBalances(t *testing.T) {
+	s, cleanup := openTestStore(t)
+	defer cleanup()
+
+	day := func(d int) time.Time { return time.Date(2020, 8, d, 0, 0, 0, 0, time.UTC) }
+	require.NoError(t, s.SaveBalances(networth.Series{AccountID: "a", Currency: "AUD", Points: []networth.Point{
+		{Date: day(1), Balance: 100},
+		{Date: day(2), Balance: 200},
+	}}))
+	require.NoError(t, s.SaveBalances(networth.Series{AccountID: "b", Currency: "AUD", Points: []networth.Point{
+		{Date: day(1), Balance: 1000},
+	}}))
+	// Saving a day again replaces it.
+	require.NoError(t, s.SaveBalances(networth.Series{AccountID: "a", Currency: "AUD", Points: []networth.Point{
+		{Date: day(2), Balance: 250},
+		{Date: day(3), Balance: 300},
+	}}))
+
+	series, err := s.Balances("a", day(2))
+	require.NoError(t, err)
+	require.Equal(t, networth.Series{AccountID: "a", Currency: "AUD", Points: []networth.Point{
+		{Date: day(2), Balance: 250},
+		{Date: day(3), Balance: 300},
+	}}, series)
+
+	series, err = s.Balances("b", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, series.Points, 1)
+}
+
+func TestSnapshotBalances(t *testing.T) {
+	s, cleanup := openTestStore(t)
+	defer cleanup()
+
+	now := time.Date(2020, 8, 10, 12, 0, 0, 0, time.UTC)
+	var account upngo.AccountResource
+	account.ID = "spending"
+	account.Attributes.Balance = upngo.MoneyObject{CurrencyCode: "AUD", ValueInBaseUnits: 500}
+	account.Attributes.CreatedAt = now.AddDate(0, 0, -3)
+	require.NoError(t, s.ReplaceAccounts([]upngo.AccountResource{account}))
+
+	transaction := newTransaction("1", "spending", upngo.TransactionStatusSettled, now.AddDate(0, 0, -2))
+	transaction.Attributes.Amount.ValueInBaseUnits = -100
+	require.NoError(t, s.reconcileTransactions([]upngo.TransactionResource{transaction}, time.Time{}, now, &SyncResult{}))
+
+	// The first snapshot goes back to when the account was created even though
+	// only today was asked for.
+	require.NoError(t, s.SnapshotBalances(now, now, time.UTC))
+	series, err := s.Balances("spending", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []networth.Point{
+		{Date: networth.Day(now.AddDate(0, 0, -3), time.UTC), Balance: 600},
+		{Date: networth.Day(now.AddDate(0, 0, -2), time.UTC), Balance: 500},
+		{Date: networth.Day(now.AddDate(0, 0, -1), time.UTC), Balance: 500},
+		{Date: networth.Day(now, time.UTC), Balance: 500},
+	}, series.Points)
+}
@@ -0,0 +1,231 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nick96/upngo"
+	bolt "go.etcd.io/bbolt"
+)
+
+// syncOverlap is how far before the high water mark we start fetching
+// transactions from. Transactions don't always show up the instant they're
+// created, so this gives them a chance to be picked up by the next sync.
+const syncOverlap = 24 * time.Hour
+
+// SyncResult summarises what changed in the store during a sync.
+type SyncResult struct {
+	Accounts   int
+	Categories int
+	Tags       int
+	// Added, Updated and Deleted are the number of transactions that were
+	// added, changed (e.g. went from HELD to SETTLED) or deleted.
+	Added   int
+	Updated int
+	Deleted int
+	// Since is the start of the window of transactions that was fetched. It
+	// is the zero time when all transactions were fetched.
+	Since time.Time
+}
+
+// Sync brings the store up to date with the API.
+//
+// Accounts, categories and tags are small so they're fetched and replaced in
+// full. Transactions are fetched incrementally from just before the last sync,
+// or the oldest transaction that is still held if that's earlier, so held
+// transactions that have since settled are updated. Any stored transaction in
+// that window that the API no longer returns has been deleted so it's removed
+// from the store too. If `full` is true then everything is fetched.
+func Sync(client *upngo.Client, s *Store, full bool) (SyncResult, error) {
+	var result SyncResult
+	start := time.Now()
+
+	accounts, err := fetchAccounts(client)
+	if err != nil {
+		return result, err
+	}
+	if err := s.ReplaceAccounts(accounts); err != nil {
+		return result, err
+	}
+	result.Accounts = len(accounts)
+
+	categories, err := client.Categories.List()
+	if err != nil {
+		return result, fmt.Errorf("failed to get categories: %w", err)
+	}
+	if err := s.ReplaceCategories(categories.Data); err != nil {
+		return result, err
+	}
+	result.Categories = len(categories.Data)
+
+	tags, err := fetchTags(client)
+	if err != nil {
+		return result, err
+	}
+	if err := s.ReplaceTags(tags); err != nil {
+		return result, err
+	}
+	result.Tags = len(tags)
+
+	if !full {
+		result.Since, err = s.syncWindowStart()
+		if err != nil {
+			return result, err
+		}
+	}
+
+	options := []upngo.TransactionsOption{upngo.WithPageSize(100)}
+	if !result.Since.IsZero() {
+		options = append(options, upngo.WithFilterSince(result.Since))
+	}
+	transactions, err := fetchTransactions(client, options...)
+	if err != nil {
+		return result, err
+	}
+
+	if err := s.reconcileTransactions(transactions, result.Since, start, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// syncWindowStart works out how far back the next sync needs to fetch
+// transactions from.
+func (s *Store) syncWindowStart() (time.Time, error) {
+	mark, err := s.HighWaterMark()
+	if err != nil || mark.IsZero() {
+		return time.Time{}, err
+	}
+
+	since := mark.Add(-syncOverlap)
+	err = s.forEach(transactionsBucket, func(data []byte) error {
+		var transaction upngo.TransactionResource
+		if err := json.Unmarshal(data, &transaction); err != nil {
+			return err
+		}
+		createdAt := transaction.Attributes.CreatedAt
+		if transaction.Attributes.Status == upngo.TransactionStatusHeld && createdAt.Before(since) {
+			since = createdAt
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read transactions from store: %w", err)
+	}
+	return since, nil
+}
+
+// reconcileTransactions makes the stored transactions created since `since`
+// match `transactions` and sets the high water mark to `mark`. It all happens
+// in one database transaction so an interrupted sync doesn't leave the store
+// half updated.
+func (s *Store) reconcileTransactions(
+	transactions []upngo.TransactionResource,
+	since time.Time,
+	mark time.Time,
+	result *SyncResult,
+) error {
+	fetched := make(map[string]bool, len(transactions))
+	for _, transaction := range transactions {
+		fetched[transaction.ID] = true
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(transactionsBucket)
+
+		// Deleting from a bucket while iterating over it isn't safe so we
+		// collect the IDs first.
+		var deleted [][]byte
+		err := bucket.ForEach(func(id, data []byte) error {
+			var transaction upngo.TransactionResource
+			if err := json.Unmarshal(data, &transaction); err != nil {
+				return err
+			}
+			if !fetched[string(id)] && !transaction.Attributes.CreatedAt.Before(since) {
+				deleted = append(deleted, id)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, id := range deleted {
+			if err := bucket.Delete(id); err != nil {
+				return err
+			}
+		}
+		result.Deleted = len(deleted)
+
+		for _, transaction := range transactions {
+			data, err := json.Marshal(transaction)
+			if err != nil {
+				return fmt.Errorf("failed to marshal transaction %s: %w", transaction.ID, err)
+			}
+
+			existing := bucket.Get([]byte(transaction.ID))
+			switch {
+			case existing == nil:
+				result.Added++
+			case !bytes.Equal(existing, data):
+				result.Updated++
+			default:
+				continue
+			}
+			if err := bucket.Put([]byte(transaction.ID), data); err != nil {
+				return err
+			}
+		}
+
+		markText, err := mark.MarshalText()
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(metaBucket).Put(highWaterMarkKey, markText)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store transactions: %w", err)
+	}
+	return nil
+}
+
+func fetchAccounts(client *upngo.Client) ([]upngo.AccountResource, error) {
+	page, err := client.Accounts.List()
+	var accounts []upngo.AccountResource
+	for err == nil {
+		accounts = append(accounts, page.Data...)
+		page, err = client.Accounts.Next(page)
+	}
+	if !errors.Is(err, upngo.ErrNoNextPage) {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	return accounts, nil
+}
+
+func fetchTags(client *upngo.Client) ([]upngo.TagResource, error) {
+	page, err := client.Tags.List()
+	var tags []upngo.TagResource
+	for err == nil {
+		tags = append(tags, page.Data...)
+		page, err = client.Tags.Next(page)
+	}
+	if !errors.Is(err, upngo.ErrNoNextPage) {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	return tags, nil
+}
+
+func fetchTransactions(client *upngo.Client, options ...upngo.TransactionsOption) ([]upngo.TransactionResource, error) {
+	page, err := client.Transactions.List(options...)
+	var transactions []upngo.TransactionResource
+	for err == nil {
+		transactions = append(transactions, page.Data...)
+		page, err = client.Transactions.Next(page)
+	}
+	if !errors.Is(err, upngo.ErrNoNextPage) {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	return transactions, nil
+}
@@ -0,0 +1,107 @@
+package upngo
+
+import "net/http"
+
+type TagRelationships struct {
+	Transactions TransactionsObject `json:"transactions"`
+}
+
+// TagResource is a tag that has been added to at least one transaction. The
+// ID is the tag's label.
+type TagResource struct {
+	Type          string           `json:"type"`
+	ID            string           `json:"id"`
+	Relationships TagRelationships `json:"relationships"`
+}
+
+// TagsResponse represents a response from the tags endpoint.
+type TagsResponse struct {
+	Data  []TagResource `json:"data"`
+	Links LinksObject   `json:"links"`
+}
+
+// TagsClient is a Client's tags endpoint, e.g. `client.Tags.List()`.
+// Calling it lists tags, like List, so code from before the endpoints were
+// grouped, `client.Tags()`, still works.
+//
+// Create one with NewTagsClient, otherwise its methods return
+// ErrNoImplementation.
+type TagsClient func(options ...TagsOption) (TagsResponse, error)
+
+// NewTagsClient creates a TagsClient that uses `api`, e.g. to replace a
+// Client's Tags with a mock.
+func NewTagsClient(api TagsAPI) TagsClient {
+	return func(options ...TagsOption) (TagsResponse, error) {
+		if len(options) == 1 && provide(options[0], api) {
+			return TagsResponse{}, nil
+		}
+		return api.List(options...)
+	}
+}
+
+// api is the implementation the sub-client wraps. Only sub-clients created
+// with NewTagsClient have one.
+func (c TagsClient) api() (TagsAPI, error) {
+	if c == nil {
+		return nil, ErrNoImplementation
+	}
+	api, ok := unwrap(func(request implementation) { c(request) }).(TagsAPI)
+	if !ok {
+		return nil, ErrNoImplementation
+	}
+	return api, nil
+}
+
+// List lists the tags that are currently on at least one transaction.
+func (c TagsClient) List(options ...TagsOption) (TagsResponse, error) {
+	if c == nil {
+		return TagsResponse{}, ErrNoImplementation
+	}
+	return c(options...)
+}
+
+// Next retrieves the page of tags after the given response. If there are no
+// more pages then ErrNoNextPage is returned.
+func (c TagsClient) Next(tags TagsResponse) (TagsResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return TagsResponse{}, err
+	}
+	return api.Next(tags)
+}
+
+// TagsService talks to the tags endpoint. It's what a Client's Tags uses
+// unless it's been replaced.
+type TagsService struct {
+	client *Client
+}
+
+// List lists the tags that are currently on at least one transaction.
+func (s *TagsService) List(options ...TagsOption) (TagsResponse, error) {
+	return s.list(s.client.buildURL("tags"), options...)
+}
+
+// Next retrieves the page of tags after the given response. If there are no
+// more pages then ErrNoNextPage is returned.
+func (s *TagsService) Next(tags TagsResponse) (TagsResponse, error) {
+	if tags.Links.Next == "" {
+		return TagsResponse{}, ErrNoNextPage
+	}
+	return s.list(tags.Links.Next)
+}
+
+func (s *TagsService) list(url string, options ...TagsOption) (TagsResponse, error) {
+	query := make([]Option, len(options))
+	for i, option := range options {
+		query[i] = option
+	}
+
+	var tagsResponse TagsResponse
+	err := s.client.do(request{
+		name:    "get tags",
+		method:  http.MethodGet,
+		url:     url,
+		options: query,
+	}, &tagsResponse)
+	return tagsResponse, err
+}
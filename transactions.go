@@ -1,12 +1,16 @@
 package upngo
 
-import "time"
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
 
 type TransactionStatus string
 
 const (
 	TransactionStatusHeld    TransactionStatus = "HELD"
-	TransactionStatusSettled TransactionStatus = "SETTELED"
+	TransactionStatusSettled TransactionStatus = "SETTLED"
 )
 
 type RelatedLinksObject struct {
@@ -23,16 +27,29 @@ type AccountObject struct {
 	Data  DataObject         `json:"data"`
 }
 
-// TagObject is an undocumented part of the transactions response. I'm not sure
-// what `Data` contains as I've only seen it as empty so far. We shall see!
+// TagObject is the tags that have been added to a transaction. The ID of each
+// element of `Data` is the tag's label.
 type TagObject struct {
-	Data  []interface{}  `json:"data"`
+	Data  []DataObject   `json:"data"`
 	Links SelfLinkObject `json:"links"`
 }
 
+// OptionalRelationshipObject is a relationship to another resource that may
+// not exist. If it doesn't then `Data` is nil.
+type OptionalRelationshipObject struct {
+	Data  *DataObject         `json:"data"`
+	Links *RelatedLinksObject `json:"links,omitempty"`
+}
+
 type TransactionRelationshipsObject struct {
 	Account AccountObject `json:"account"`
-	Tags    TagObject     `json:"tags"`
+	// TransferAccount is the account the money went to or came from when the
+	// transaction is a transfer between accounts. The sign of the
+	// transaction's amount says which direction it went.
+	TransferAccount OptionalRelationshipObject `json:"transferAccount"`
+	Category        OptionalRelationshipObject `json:"category"`
+	ParentCategory  OptionalRelationshipObject `json:"parentCategory"`
+	Tags            TagObject                  `json:"tags"`
 }
 
 type Resource struct {
@@ -85,3 +102,202 @@ type TransactionResponse struct {
 	Data  TransactionResource `json:"data"`
 	Links LinksObject         `json:"links"`
 }
+
+// RelationshipRequest is the body of a request to change a to-one relationship,
+// such as a transaction's category. A nil `Data` removes the relationship.
+type RelationshipRequest struct {
+	Data *DataObject `json:"data"`
+}
+
+// RelationshipsRequest is the body of a request to change a to-many
+// relationship, such as a transaction's tags.
+type RelationshipsRequest struct {
+	Data []DataObject `json:"data"`
+}
+
+// TransactionsClient is a Client's transactions endpoints, e.g.
+// `client.Transactions.Get(id)`. Calling it lists transactions, like List, so
+// code from before the endpoints were grouped, `client.Transactions()`, still
+// works.
+//
+// Create one with NewTransactionsClient, otherwise its methods return
+// ErrNoImplementation.
+type TransactionsClient func(options ...TransactionsOption) (TransactionsResponse, error)
+
+// NewTransactionsClient creates a TransactionsClient that uses `api`, e.g. to
+// replace a Client's Transactions with a mock.
+func NewTransactionsClient(api TransactionsAPI) TransactionsClient {
+	return func(options ...TransactionsOption) (TransactionsResponse, error) {
+		if len(options) == 1 && provide(options[0], api) {
+			return TransactionsResponse{}, nil
+		}
+		return api.List(options...)
+	}
+}
+
+// api is the implementation the sub-client wraps. Only sub-clients created
+// with NewTransactionsClient have one.
+func (c TransactionsClient) api() (TransactionsAPI, error) {
+	if c == nil {
+		return nil, ErrNoImplementation
+	}
+	api, ok := unwrap(func(request implementation) { c(request) }).(TransactionsAPI)
+	if !ok {
+		return nil, ErrNoImplementation
+	}
+	return api, nil
+}
+
+// List lists all the transactions associated with the authenticated account.
+func (c TransactionsClient) List(options ...TransactionsOption) (TransactionsResponse, error) {
+	if c == nil {
+		return TransactionsResponse{}, ErrNoImplementation
+	}
+	return c(options...)
+}
+
+// Next retrieves the page of transactions after the given response. If there
+// are no more pages then ErrNoNextPage is returned.
+func (c TransactionsClient) Next(transactions TransactionsResponse) (TransactionsResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return TransactionsResponse{}, err
+	}
+	return api.Next(transactions)
+}
+
+// Get retrieves a transaction by its ID.
+func (c TransactionsClient) Get(id string) (TransactionResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return TransactionResponse{}, err
+	}
+	return api.Get(id)
+}
+
+// Categorize sets the category of the transaction. An empty `categoryID`
+// removes the transaction's category.
+func (c TransactionsClient) Categorize(transactionID string, categoryID string) error {
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	return api.Categorize(transactionID, categoryID)
+}
+
+// Tag adds the tags to the transaction.
+func (c TransactionsClient) Tag(transactionID string, tags ...string) error {
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	return api.Tag(transactionID, tags...)
+}
+
+// Untag removes the tags from the transaction.
+func (c TransactionsClient) Untag(transactionID string, tags ...string) error {
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	return api.Untag(transactionID, tags...)
+}
+
+// TransactionsService talks to the transactions endpoints. It's what a
+// Client's Transactions uses unless it's been replaced.
+type TransactionsService struct {
+	client *Client
+}
+
+// List lists all the transactions associated with the authenticated account.
+func (s *TransactionsService) List(options ...TransactionsOption) (TransactionsResponse, error) {
+	return s.list(s.client.buildURL("transactions"), options...)
+}
+
+// Next retrieves the page of transactions after the given response. If there
+// are no more pages then ErrNoNextPage is returned.
+func (s *TransactionsService) Next(transactions TransactionsResponse) (TransactionsResponse, error) {
+	if transactions.Links.Next == "" {
+		return TransactionsResponse{}, ErrNoNextPage
+	}
+	// The next link already has all the options from the original request
+	// baked into it so we don't need to add any.
+	return s.list(transactions.Links.Next)
+}
+
+func (s *TransactionsService) list(url string, options ...TransactionsOption) (TransactionsResponse, error) {
+	query := make([]Option, len(options))
+	for i, option := range options {
+		query[i] = option
+	}
+
+	var transactionsResponse TransactionsResponse
+	err := s.client.do(request{
+		name:    "get transactions",
+		method:  http.MethodGet,
+		url:     url,
+		options: query,
+	}, &transactionsResponse)
+	return transactionsResponse, err
+}
+
+// Get retrieves a transaction by its ID.
+func (s *TransactionsService) Get(id string) (TransactionResponse, error) {
+	var transactionResponse TransactionResponse
+	err := s.client.do(request{
+		name:   "get transaction by ID",
+		method: http.MethodGet,
+		url:    s.client.buildURL("transactions", id),
+	}, &transactionResponse)
+	return transactionResponse, err
+}
+
+// sendRelationship sends a request to change a relationship of a transaction.
+// The API responds with no content so there's nothing to return but the error.
+func (s *TransactionsService) sendRelationship(method, transactionID, relationship string, body interface{}) error {
+	return s.client.do(request{
+		name:   "relationship",
+		method: method,
+		url:    s.client.buildURL("transactions", transactionID, "relationships", relationship),
+		body:   body,
+		status: http.StatusNoContent,
+	}, nil)
+}
+
+// Categorize sets the category of the transaction. An empty `categoryID`
+// removes the transaction's category.
+func (s *TransactionsService) Categorize(transactionID string, categoryID string) error {
+	body := RelationshipRequest{}
+	if categoryID != "" {
+		body.Data = &DataObject{Type: "categories", ID: categoryID}
+	}
+	if err := s.sendRelationship(http.MethodPatch, transactionID, "category", body); err != nil {
+		return fmt.Errorf("failed to categorize transaction %s: %w", transactionID, err)
+	}
+	return nil
+}
+
+func tagsRequest(tags []string) RelationshipsRequest {
+	body := RelationshipsRequest{Data: []DataObject{}}
+	for _, tag := range tags {
+		body.Data = append(body.Data, DataObject{Type: "tags", ID: tag})
+	}
+	return body
+}
+
+// Tag adds the tags to the transaction. Tags that are already on the
+// transaction are left alone.
+func (s *TransactionsService) Tag(transactionID string, tags ...string) error {
+	if err := s.sendRelationship(http.MethodPost, transactionID, "tags", tagsRequest(tags)); err != nil {
+		return fmt.Errorf("failed to add tags to transaction %s: %w", transactionID, err)
+	}
+	return nil
+}
+
+// Untag removes the tags from the transaction.
+func (s *TransactionsService) Untag(transactionID string, tags ...string) error {
+	if err := s.sendRelationship(http.MethodDelete, transactionID, "tags", tagsRequest(tags)); err != nil {
+		return fmt.Errorf("failed to remove tags from transaction %s: %w", transactionID, err)
+	}
+	return nil
+}
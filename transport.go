@@ -1,9 +1,16 @@
 package upngo
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type addAuthorizationHeaderTransport struct {
@@ -24,22 +31,233 @@ func newAddAuthorizationHeaderTransport(roundTripper http.RoundTripper, token st
 }
 
 type logTransport struct {
-	rt http.RoundTripper
+	rt     http.RoundTripper
+	logger Logger
+	level  LogLevel
+	token  string
 }
 
 func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	log.Printf("--> %s %s", req.Method, req.URL.String())
+	if t.level == LogOff {
+		return t.rt.RoundTrip(req)
+	}
+
+	t.logf("--> %s %s", req.Method, req.URL.String())
+	if t.level >= LogBodies {
+		t.logHeaders("-->", req.Header)
+		if req.Body != nil && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err == nil {
+				t.logBody("-->", body)
+			}
+		}
+	}
+
 	resp, err := t.rt.RoundTrip(req)
 	if err != nil {
+		t.logf("<-- %s %s: %v", req.Method, req.URL.String(), err)
 		return resp, err
 	}
-	log.Printf("<-- %s %s", resp.Status, resp.Request.URL)
-	return resp, err
+
+	t.logf("<-- %s %s", resp.Status, resp.Request.URL)
+	if t.level >= LogBodies {
+		t.logHeaders("<--", resp.Header)
+		// Put the body back after reading it so the caller can still read
+		// it.
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			// The body's already been closed so the response is no use.
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		t.logBody("<--", ioutil.NopCloser(bytes.NewReader(body)))
+	}
+	return resp, nil
+}
+
+func (t *logTransport) logf(format string, args ...interface{}) {
+	t.logger.Printf("%s", redactToken(fmt.Sprintf(format, args...), t.token))
 }
 
-func newLogTransport(rt http.RoundTripper) *logTransport {
+func (t *logTransport) logHeaders(prefix string, header http.Header) {
+	header = redactHeaders(header)
+	var names []string
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.logf("%s %s: %s", prefix, name, strings.Join(header[name], ", "))
+	}
+}
+
+func (t *logTransport) logBody(prefix string, body io.ReadCloser) {
+	defer body.Close()
+	contents, err := ioutil.ReadAll(body)
+	if err != nil || len(contents) == 0 {
+		return
+	}
+	t.logf("%s %s", prefix, redactBody(contents))
+}
+
+func newLogTransport(rt http.RoundTripper, logger Logger, level LogLevel, token string) *logTransport {
 	if rt == nil {
 		rt = http.DefaultTransport
 	}
-	return &logTransport{rt}
+	if logger == nil {
+		level = LogOff
+	}
+	return &logTransport{rt, logger, level, token}
+}
+
+type observeTransport struct {
+	rt       http.RoundTripper
+	observer Observer
+}
+
+func (t *observeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.rt.RoundTrip(req)
+
+	info := RequestInfo{
+		Method:   req.Method,
+		Endpoint: Endpoint(req),
+		Duration: time.Since(start),
+		Attempt:  attemptFrom(req.Context()),
+		Err:      err,
+	}
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+	}
+	t.observer.ObserveRequest(info)
+
+	return resp, err
+}
+
+func newObserveTransport(rt http.RoundTripper, observer Observer) http.RoundTripper {
+	if observer == nil {
+		return rt
+	}
+	return &observeTransport{rt, observer}
+}
+
+type retryTransport struct {
+	rt         http.RoundTripper
+	maxRetries int
+	backoff    time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	wait := t.backoff
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.WithContext(withAttempt(req.Context(), attempt))
+		if attempt > 1 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body to retry: %w", err)
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := t.rt.RoundTrip(attemptReq)
+		if attempt > t.maxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := wait
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		wait *= 2
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry reports whether a request is worth retrying. Only idempotent
+// requests are retried, and only if they failed in a way that might not
+// happen again. Going over our own rate limit isn't retried, it's already
+// waited as long as it can.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil && !errors.Is(err, ErrRateLimited)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of
+// seconds or a date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+func newRetryTransport(rt http.RoundTripper, maxRetries int, backoff time.Duration) http.RoundTripper {
+	if maxRetries <= 0 {
+		return rt
+	}
+	return &retryTransport{rt, maxRetries, backoff}
+}
+
+type traceTransport struct {
+	rt     http.RoundTripper
+	tracer Tracer
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := Endpoint(req)
+	ctx, span := t.tracer.Start(req.Context(), "upngo "+req.Method+" "+name)
+	defer span.End()
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.route", name)
+
+	resp, err := t.rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		return resp, err
+	}
+	span.SetAttribute("http.status_code", resp.StatusCode)
+	return resp, err
+}
+
+func newTraceTransport(rt http.RoundTripper, tracer Tracer) http.RoundTripper {
+	if tracer == nil {
+		return rt
+	}
+	if _, ok := tracer.(NoopTracer); ok {
+		return rt
+	}
+	return &traceTransport{rt, tracer}
 }
@@ -0,0 +1,539 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/nick96/upngo"
+	"github.com/rivo/tview"
+)
+
+// loadAhead is how close to the last loaded transaction the selection has to
+// get before the next page is loaded.
+const loadAhead = 5
+
+const help = "[yellow]tab[-] switch pane  [yellow]enter[-] choose account  " +
+	"[yellow]t[-] tag  [yellow]c[-] categorize  [yellow]f[-] filter  [yellow]r[-] reload  [yellow]q[-] quit"
+
+// Options changes what the TUI shows first and how it shows it.
+type Options struct {
+	// AccountID is the account whose transactions are shown first. If it's
+	// empty, transactions in all accounts are shown.
+	AccountID string
+	// PageSize is how many transactions are fetched at a time.
+	PageSize int
+	// Location is the timezone dates are shown in.
+	Location *time.Location
+	// DateLayout is how dates are shown and entered in the filter.
+	DateLayout string
+}
+
+// app is the state of the TUI. Everything in it belongs to tview's event loop,
+// so requests are made in their own goroutine and their results are passed
+// back with QueueUpdateDraw.
+type app struct {
+	client     *upngo.Client
+	options    Options
+	accounts   []upngo.AccountResource
+	categories []upngo.CategoryResource
+	// names are the display names of accounts and categories by ID.
+	names map[string]string
+
+	accountID    string
+	filter       Filter
+	browser      *browser
+	loading      bool
+	transactions []upngo.TransactionResource
+
+	application       *tview.Application
+	pages             *tview.Pages
+	accountsTable     *tview.Table
+	transactionsTable *tview.Table
+	detail            *tview.TextView
+	status            *tview.TextView
+}
+
+// Run shows the TUI until it's quit. The accounts and categories are fetched
+// before it starts, so it fails straight away if the API can't be used.
+func Run(client *upngo.Client, options Options) error {
+	if options.Location == nil {
+		options.Location = time.Local
+	}
+	if options.DateLayout == "" {
+		options.DateLayout = "2006-01-02"
+	}
+
+	a := &app{
+		client:    client,
+		options:   options,
+		names:     make(map[string]string),
+		accountID: options.AccountID,
+	}
+	if err := a.loadReferenceData(); err != nil {
+		return err
+	}
+
+	a.layout()
+	a.showTransactions()
+	if err := a.application.Run(); err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	return nil
+}
+
+func (a *app) loadReferenceData() error {
+	accounts, err := a.client.Accounts.List()
+	for err == nil {
+		a.accounts = append(a.accounts, accounts.Data...)
+		accounts, err = a.client.Accounts.Next(accounts)
+	}
+	if !errors.Is(err, upngo.ErrNoNextPage) {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	categories, err := a.client.Categories.List()
+	if err != nil {
+		return fmt.Errorf("failed to get categories: %w", err)
+	}
+	a.categories = categories.Data
+	sort.Slice(a.categories, func(i, j int) bool {
+		return a.categories[i].Attributes.Name < a.categories[j].Attributes.Name
+	})
+
+	for _, account := range a.accounts {
+		a.names[account.ID] = account.Attributes.DisplayName
+	}
+	for _, category := range a.categories {
+		a.names[category.ID] = category.Attributes.Name
+	}
+	return nil
+}
+
+func (a *app) layout() {
+	a.accountsTable = tview.NewTable().SetSelectable(true, false)
+	a.accountsTable.SetBorder(true).SetTitle(" Accounts ")
+	a.accountsTable.SetCell(0, 0, tview.NewTableCell("All accounts").SetExpansion(1))
+	for i, account := range a.accounts {
+		balance := account.Attributes.Balance
+		a.accountsTable.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(account.Attributes.DisplayName)).SetExpansion(1))
+		a.accountsTable.SetCell(i+1, 1, tview.NewTableCell(upngo.FormatBaseUnits(balance.ValueInBaseUnits, balance.CurrencyCode)).SetAlign(tview.AlignRight))
+		if account.ID == a.accountID {
+			a.accountsTable.Select(i+1, 0)
+		}
+	}
+	a.accountsTable.SetSelectedFunc(func(row, column int) {
+		a.accountID = ""
+		if row > 0 {
+			a.accountID = a.accounts[row-1].ID
+		}
+		a.showTransactions()
+		a.application.SetFocus(a.transactionsTable)
+	})
+
+	a.transactionsTable = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	a.transactionsTable.SetBorder(true)
+	a.transactionsTable.SetSelectionChangedFunc(func(row, column int) {
+		a.showDetail()
+		if row >= len(a.transactions)-loadAhead {
+			a.loadMore()
+		}
+	})
+
+	a.detail = tview.NewTextView().SetWrap(true)
+	a.detail.SetBorder(true).SetTitle(" Details ")
+
+	a.status = tview.NewTextView().SetDynamicColors(true)
+	a.setStatus("")
+
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.transactionsTable, 0, 2, true).
+		AddItem(a.detail, 0, 1, false)
+	main := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(a.accountsTable, 40, 0, false).
+			AddItem(right, 0, 1, true), 0, 1, true).
+		AddItem(a.status, 1, 0, false)
+	a.pages = tview.NewPages().AddPage("main", main, true, true)
+
+	a.application = tview.NewApplication().SetRoot(a.pages, true).SetFocus(a.transactionsTable)
+	a.application.SetInputCapture(a.handleKey)
+}
+
+func (a *app) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	// Keys typed into dialogs are theirs.
+	if a.pages.HasPage("dialog") {
+		return event
+	}
+
+	switch event.Key() {
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if a.accountsTable.HasFocus() {
+			a.application.SetFocus(a.transactionsTable)
+		} else {
+			a.application.SetFocus(a.accountsTable)
+		}
+		return nil
+	case tcell.KeyRune:
+	default:
+		return event
+	}
+
+	switch event.Rune() {
+	case 'q':
+		a.application.Stop()
+	case 't':
+		a.tagDialog()
+	case 'c':
+		a.categorizeDialog()
+	case 'f':
+		a.filterDialog()
+	case 'r':
+		a.showTransactions()
+	default:
+		return event
+	}
+	return nil
+}
+
+// showTransactions starts showing transactions again from the newest, e.g.
+// after choosing another account or changing the filter.
+func (a *app) showTransactions() {
+	a.browser = newBrowser(a.client, a.options.PageSize, a.accountID, a.filter)
+	a.loading = false
+	a.transactions = nil
+
+	title := "All accounts"
+	if name, ok := a.names[a.accountID]; ok {
+		title = name
+	}
+	if description := a.filter.describe(a.options.DateLayout); description != "" {
+		title += " (" + description + ")"
+	}
+	a.transactionsTable.SetTitle(" " + tview.Escape(title) + " ")
+	a.transactionsTable.Clear()
+	for column, heading := range []string{"Date", "Description", "Amount", ""} {
+		cell := tview.NewTableCell(heading).SetSelectable(false).SetAttributes(tcell.AttrBold)
+		if heading == "Amount" {
+			cell.SetAlign(tview.AlignRight)
+		}
+		a.transactionsTable.SetCell(0, column, cell)
+	}
+	a.transactionsTable.Select(0, 0).ScrollToBeginning()
+	a.loadMore()
+}
+
+// loadMore loads the next page of transactions in the background, unless it's
+// already loading or there aren't any more.
+func (a *app) loadMore() {
+	if a.loading || a.browser.done {
+		return
+	}
+	a.loading = true
+	a.setStatus("Loading transactions...")
+
+	b := a.browser
+	go func() {
+		transactions, err := b.more()
+		a.application.QueueUpdateDraw(func() {
+			// The transactions were for a different account or filter.
+			if b != a.browser {
+				return
+			}
+			a.loading = false
+			if err != nil {
+				a.setError("Failed to get transactions: %v", err)
+				return
+			}
+			a.setStatus("")
+			a.addTransactions(transactions)
+		})
+	}()
+}
+
+func (a *app) addTransactions(transactions []upngo.TransactionResource) {
+	for _, transaction := range transactions {
+		a.transactions = append(a.transactions, transaction)
+		a.setTransactionRow(len(a.transactions) - 1)
+	}
+	if len(a.transactions) == 0 {
+		a.detail.SetText("No transactions.")
+		return
+	}
+	if row, _ := a.transactionsTable.GetSelection(); row == 0 {
+		a.transactionsTable.Select(1, 0)
+	}
+}
+
+func (a *app) setTransactionRow(index int) {
+	transaction := a.transactions[index]
+	attributes := transaction.Attributes
+	row := index + 1
+
+	amount := tview.NewTableCell(upngo.FormatBaseUnits(attributes.Amount.ValueInBaseUnits, attributes.Amount.CurrencyCode)).
+		SetAlign(tview.AlignRight)
+	if attributes.Amount.ValueInBaseUnits > 0 {
+		amount.SetTextColor(tcell.ColorGreen)
+	}
+	status := ""
+	if attributes.Status == upngo.TransactionStatusHeld {
+		status = "held"
+	}
+
+	a.transactionsTable.SetCell(row, 0, tview.NewTableCell(attributes.CreatedAt.In(a.options.Location).Format(a.options.DateLayout)))
+	a.transactionsTable.SetCell(row, 1, tview.NewTableCell(tview.Escape(attributes.Description)).SetExpansion(1))
+	a.transactionsTable.SetCell(row, 2, amount)
+	a.transactionsTable.SetCell(row, 3, tview.NewTableCell(status).SetTextColor(tcell.ColorYellow))
+}
+
+// selected is the index of the selected transaction, or -1 if none is.
+func (a *app) selected() int {
+	row, _ := a.transactionsTable.GetSelection()
+	if row < 1 || row > len(a.transactions) {
+		return -1
+	}
+	return row - 1
+}
+
+func (a *app) showDetail() {
+	index := a.selected()
+	if index < 0 {
+		a.detail.Clear()
+		return
+	}
+	a.detail.SetText(details(a.transactions[index], a.names, a.options.Location, a.options.DateLayout))
+	a.detail.ScrollToBeginning()
+}
+
+// change makes a change to the selected transaction in the background and
+// then gets it again to show the change.
+func (a *app) change(done string, request func(id string) error) {
+	index := a.selected()
+	if index < 0 {
+		return
+	}
+	id := a.transactions[index].ID
+	a.setStatus("Saving...")
+
+	go func() {
+		var transaction upngo.TransactionResponse
+		err := request(id)
+		if err == nil {
+			transaction, err = a.client.Transactions.Get(id)
+		}
+		a.application.QueueUpdateDraw(func() {
+			if err != nil {
+				a.setError("%v", err)
+				return
+			}
+			a.setStatus(done)
+			// The transactions might have been reloaded in the meantime, so
+			// find it again.
+			for i := range a.transactions {
+				if a.transactions[i].ID == id {
+					a.transactions[i] = transaction.Data
+					a.setTransactionRow(i)
+				}
+			}
+			a.showDetail()
+		})
+	}()
+}
+
+func (a *app) tagDialog() {
+	index := a.selected()
+	if index < 0 {
+		return
+	}
+	var current []string
+	for _, tag := range a.transactions[index].Relationships.Tags.Data {
+		current = append(current, tag.ID)
+	}
+
+	form := tview.NewForm().AddInputField("Tags", strings.Join(current, ", "), 40, nil, nil)
+	form.AddButton("Save", func() {
+		tags := splitTags(form.GetFormItemByLabel("Tags").(*tview.InputField).GetText())
+		added, removed := diffTags(current, tags)
+		a.closeDialog()
+		a.change("Tags saved", func(id string) error {
+			if len(added) > 0 {
+				if err := a.client.Transactions.Tag(id, added...); err != nil {
+					return err
+				}
+			}
+			if len(removed) > 0 {
+				return a.client.Transactions.Untag(id, removed...)
+			}
+			return nil
+		})
+	})
+	form.AddButton("Cancel", a.closeDialog)
+	form.SetBorder(true).SetTitle(" Tag (comma separated) ")
+	a.openDialog(form, 60, 7)
+}
+
+func (a *app) categorizeDialog() {
+	index := a.selected()
+	if index < 0 {
+		return
+	}
+
+	// Only categories without children can be given to transactions.
+	options := []string{"None"}
+	ids := []string{""}
+	initial := 0
+	current := a.transactions[index].Relationships.Category.Data
+	for _, category := range a.categories {
+		if len(category.Relationships.Children.Data) > 0 {
+			continue
+		}
+		if current != nil && current.ID == category.ID {
+			initial = len(ids)
+		}
+		options = append(options, category.Attributes.Name)
+		ids = append(ids, category.ID)
+	}
+
+	form := tview.NewForm().AddDropDown("Category", options, initial, nil)
+	form.AddButton("Save", func() {
+		chosen, _ := form.GetFormItemByLabel("Category").(*tview.DropDown).GetCurrentOption()
+		a.closeDialog()
+		a.change("Category saved", func(id string) error {
+			return a.client.Transactions.Categorize(id, ids[chosen])
+		})
+	})
+	form.AddButton("Cancel", a.closeDialog)
+	form.SetBorder(true).SetTitle(" Categorize ")
+	a.openDialog(form, 60, 7)
+}
+
+func (a *app) filterDialog() {
+	format := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.In(a.options.Location).Format(a.options.DateLayout)
+	}
+	statuses := make([]string, len(Statuses))
+	initial := 0
+	for i, status := range Statuses {
+		statuses[i] = string(status)
+		if status == a.filter.Status {
+			initial = i
+		}
+	}
+
+	form := tview.NewForm().
+		AddInputField("Since", format(a.filter.Since), 12, nil, nil).
+		AddInputField("Until", format(a.filter.Until), 12, nil, nil).
+		AddDropDown("Status", statuses, initial, nil)
+	form.AddButton("Apply", func() {
+		since, err := a.parseDate(form.GetFormItemByLabel("Since").(*tview.InputField).GetText())
+		if err != nil {
+			a.setError("%v", err)
+			return
+		}
+		until, err := a.parseDate(form.GetFormItemByLabel("Until").(*tview.InputField).GetText())
+		if err != nil {
+			a.setError("%v", err)
+			return
+		}
+		// Until is inclusive, so it's up to the start of the next day.
+		if !until.IsZero() {
+			until = until.AddDate(0, 0, 1)
+		}
+		status, _ := form.GetFormItemByLabel("Status").(*tview.DropDown).GetCurrentOption()
+
+		a.filter = Filter{Since: since, Until: until, Status: Statuses[status]}
+		a.closeDialog()
+		a.showTransactions()
+	})
+	form.AddButton("Clear", func() {
+		a.filter = Filter{}
+		a.closeDialog()
+		a.showTransactions()
+	})
+	form.AddButton("Cancel", a.closeDialog)
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" Filter (dates are %s) ", a.options.DateLayout))
+	a.openDialog(form, 60, 11)
+}
+
+func (a *app) parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.ParseInLocation(a.options.DateLayout, value, a.options.Location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected the format %s", value, a.options.DateLayout)
+	}
+	return date, nil
+}
+
+// openDialog shows a dialog `width` by `height` in the middle of the screen.
+func (a *app) openDialog(dialog *tview.Form, width, height int) {
+	dialog.SetCancelFunc(a.closeDialog)
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(dialog, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+	a.pages.AddPage("dialog", centered, true, true)
+	a.application.SetFocus(dialog)
+}
+
+func (a *app) closeDialog() {
+	a.pages.RemovePage("dialog")
+	a.application.SetFocus(a.transactionsTable)
+}
+
+// setStatus shows a message in the status bar, or the keys if it's empty.
+func (a *app) setStatus(message string) {
+	if message == "" {
+		a.status.SetText(help)
+		return
+	}
+	a.status.SetText(tview.Escape(message))
+}
+
+func (a *app) setError(format string, args ...interface{}) {
+	a.status.SetText("[red]" + tview.Escape(fmt.Sprintf(format, args...)) + "[-]")
+}
+
+// splitTags splits comma separated tags, ignoring blanks.
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// diffTags works out which tags to add and remove to get from `current` to
+// `wanted`.
+func diffTags(current, wanted []string) (added, removed []string) {
+	has := make(map[string]bool)
+	for _, tag := range current {
+		has[tag] = true
+	}
+	wants := make(map[string]bool)
+	for _, tag := range wanted {
+		wants[tag] = true
+		if !has[tag] {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range current {
+		if !wants[tag] {
+			removed = append(removed, tag)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,190 @@
+// Package tui is an interactive terminal UI for browsing accounts and
+// transactions, and tagging and categorizing them.
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nick96/upngo"
+)
+
+// Status is which transactions to show by their status.
+type Status string
+
+const (
+	StatusAll     Status = "all"
+	StatusHeld    Status = "held"
+	StatusSettled Status = "settled"
+)
+
+// Statuses are the statuses transactions can be filtered by.
+var Statuses = []Status{StatusAll, StatusHeld, StatusSettled}
+
+// Filter narrows down the transactions shown. It's sent to the API so only the
+// transactions that match are fetched. The zero value shows all of them.
+type Filter struct {
+	// Since and Until can be zero to leave that end open.
+	Since  time.Time
+	Until  time.Time
+	Status Status
+}
+
+// describe summarises the filter for the transactions pane's title.
+func (f Filter) describe(layout string) string {
+	var parts []string
+	if !f.Since.IsZero() {
+		parts = append(parts, "since "+f.Since.Format(layout))
+	}
+	if !f.Until.IsZero() {
+		parts = append(parts, "until "+f.Until.AddDate(0, 0, -1).Format(layout))
+	}
+	if f.Status != "" && f.Status != StatusAll {
+		parts = append(parts, string(f.Status))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// browser pages through the transactions in an account, or all accounts, a
+// page at a time so only as many are fetched as are looked at. It only keeps
+// track of where it's up to, not the transactions themselves, so the screen
+// can own those. It isn't safe for concurrent use.
+type browser struct {
+	client    *upngo.Client
+	pageSize  int
+	accountID string
+	filter    Filter
+
+	last    upngo.TransactionsResponse
+	started bool
+	done    bool
+}
+
+// newBrowser starts browsing from the newest transaction in the account with
+// the given ID, or all accounts if it's empty.
+func newBrowser(client *upngo.Client, pageSize int, accountID string, filter Filter) *browser {
+	return &browser{client: client, pageSize: pageSize, accountID: accountID, filter: filter}
+}
+
+// more fetches the next page of transactions. Once there are no more it
+// returns nothing.
+func (b *browser) more() ([]upngo.TransactionResource, error) {
+	if b.done {
+		return nil, nil
+	}
+
+	page, err := b.next()
+	if errors.Is(err, upngo.ErrNoNextPage) {
+		b.done = true
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.last = page
+	if page.Links.Next == "" {
+		b.done = true
+	}
+	return page.Data, nil
+}
+
+func (b *browser) next() (upngo.TransactionsResponse, error) {
+	if b.started {
+		return b.client.Transactions.Next(b.last)
+	}
+	b.started = true
+
+	options := []upngo.TransactionsOption{upngo.WithPageSize(b.pageSize)}
+	if !b.filter.Since.IsZero() {
+		options = append(options, upngo.WithFilterSince(b.filter.Since))
+	}
+	if !b.filter.Until.IsZero() {
+		options = append(options, upngo.WithFilterUntil(b.filter.Until))
+	}
+	switch b.filter.Status {
+	case StatusHeld:
+		options = append(options, upngo.WithFilterStatus(upngo.TransactionStatusHeld))
+	case StatusSettled:
+		options = append(options, upngo.WithFilterStatus(upngo.TransactionStatusSettled))
+	}
+	if b.accountID == "" {
+		return b.client.Transactions.List(options...)
+	}
+	return b.client.Accounts.Transactions(b.accountID, options...)
+}
+
+// details describes every attribute and relationship of a transaction, one per
+// line. Parts that don't apply to the transaction, like cashback, are left out.
+// Accounts and categories are shown by name where they're known.
+func details(transaction upngo.TransactionResource, names map[string]string, loc *time.Location, layout string) string {
+	attributes := transaction.Attributes
+	relationships := transaction.Relationships
+
+	var lines []string
+	add := func(label, format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf("%-16s", label+":")+fmt.Sprintf(format, args...))
+	}
+	name := func(id string) string {
+		if n, ok := names[id]; ok {
+			return n
+		}
+		return id
+	}
+
+	add("ID", "%s", transaction.ID)
+	add("Description", "%s", attributes.Description)
+	add("Status", "%s", attributes.Status)
+	add("Amount", "%s %s", attributes.Amount.Value, attributes.Amount.CurrencyCode)
+	if attributes.ForeignAmount.CurrencyCode != "" {
+		add("Foreign amount", "%s %s", attributes.ForeignAmount.Value, attributes.ForeignAmount.CurrencyCode)
+	}
+	if attributes.RawText != "" {
+		add("Raw text", "%s", attributes.RawText)
+	}
+	if attributes.Message != "" {
+		add("Message", "%s", attributes.Message)
+	}
+	if hold := attributes.HoldInfo; hold.Amount.CurrencyCode != "" {
+		add("Held amount", "%s %s", hold.Amount.Value, hold.Amount.CurrencyCode)
+		if hold.ForeignAmount.CurrencyCode != "" {
+			add("Held foreign", "%s %s", hold.ForeignAmount.Value, hold.ForeignAmount.CurrencyCode)
+		}
+	}
+	if roundUp := attributes.RoundUp; roundUp.Amount.CurrencyCode != "" {
+		add("Round up", "%s %s", roundUp.Amount.Value, roundUp.Amount.CurrencyCode)
+		if roundUp.BoostPortion.CurrencyCode != "" {
+			add("Boost", "%s %s", roundUp.BoostPortion.Value, roundUp.BoostPortion.CurrencyCode)
+		}
+	}
+	if cashback := attributes.Cashback; cashback.Amount.CurrencyCode != "" {
+		add("Cashback", "%s %s (%s)", cashback.Amount.Value, cashback.Amount.CurrencyCode, cashback.Description)
+	}
+	add("Created", "%s", attributes.CreatedAt.In(loc).Format(layout+" 15:04"))
+	if !attributes.SettledAt.IsZero() {
+		add("Settled", "%s", attributes.SettledAt.In(loc).Format(layout+" 15:04"))
+	}
+
+	add("Account", "%s", name(relationships.Account.Data.ID))
+	if transfer := relationships.TransferAccount.Data; transfer != nil {
+		add("Transfer", "%s", name(transfer.ID))
+	}
+	if category := relationships.Category.Data; category != nil {
+		if parent := relationships.ParentCategory.Data; parent != nil {
+			add("Category", "%s (%s)", name(category.ID), name(parent.ID))
+		} else {
+			add("Category", "%s", name(category.ID))
+		}
+	}
+	var tags []string
+	for _, tag := range relationships.Tags.Data {
+		tags = append(tags, tag.ID)
+	}
+	sort.Strings(tags)
+	if len(tags) > 0 {
+		add("Tags", "%s", strings.Join(tags, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
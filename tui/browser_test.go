@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+func newTransaction(id string, status upngo.TransactionStatus) upngo.TransactionResource {
+	var transaction upngo.TransactionResource
+	transaction.ID = id
+	transaction.Attributes.Status = status
+	return transaction
+}
+
+// pagedServer serves the transactions three pages, linked by their next links.
+func pagedServer(t *testing.T, queries *[]string) *httptest.Server {
+	pages := [][]upngo.TransactionResource{
+		{newTransaction("1", upngo.TransactionStatusHeld), newTransaction("2", upngo.TransactionStatusSettled)},
+		{newTransaction("3", upngo.TransactionStatusSettled), newTransaction("4", upngo.TransactionStatusSettled)},
+		{newTransaction("5", upngo.TransactionStatusHeld)},
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/api/v1/accounts/spending/transactions", req.URL.Path)
+		*queries = append(*queries, req.URL.RawQuery)
+
+		page := 0
+		if cursor := req.URL.Query().Get("page[after]"); cursor != "" {
+			page = int(cursor[0] - '0')
+		}
+		response := upngo.TransactionsResponse{Data: pages[page]}
+		if page+1 < len(pages) {
+			response.Links.Next = server.URL + "/api/v1/accounts/spending/transactions?page%5Bafter%5D=" + string(rune('0'+page+1))
+		}
+		require.NoError(t, json.NewEncoder(rw).Encode(response))
+	}))
+	return server
+}
+
+func ids(transactions []upngo.TransactionResource) []string {
+	var ids []string
+	for _, transaction := range transactions {
+		ids = append(ids, transaction.ID)
+	}
+	return ids
+}
+
+func TestBrowserMore(t *testing.T) {
+	var queries []string
+	server := pagedServer(t, &queries)
+	defer server.Close()
+	client := upngo.NewClient("token", upngo.WithBaseURL(server.URL))
+
+	since := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	b := newBrowser(client, 2, "spending", Filter{Since: since})
+
+	var pages [][]string
+	for !b.done {
+		transactions, err := b.more()
+		require.NoError(t, err)
+		pages = append(pages, ids(transactions))
+	}
+	require.Equal(t, [][]string{{"1", "2"}, {"3", "4"}, {"5"}}, pages)
+
+	// Only the first request has the options, the rest follow the links.
+	require.Len(t, queries, 3)
+	require.Contains(t, queries[0], "page%5Bsize%5D=2")
+	require.Contains(t, queries[0], "filter%5Bsince%5D=2020-08-01T00%3A00%3A00Z")
+	require.Equal(t, "page%5Bafter%5D=2", queries[2])
+
+	// Once it's done it doesn't make any more requests.
+	transactions, err := b.more()
+	require.NoError(t, err)
+	require.Empty(t, transactions)
+	require.Len(t, queries, 3)
+}
+
+func TestBrowserMoreFiltersStatus(t *testing.T) {
+	var queries []string
+	server := pagedServer(t, &queries)
+	defer server.Close()
+	client := upngo.NewClient("token", upngo.WithBaseURL(server.URL))
+
+	b := newBrowser(client, 2, "spending", Filter{Status: StatusHeld})
+	_, err := b.more()
+	require.NoError(t, err)
+	require.Contains(t, queries[0], "filter%5Bstatus%5D=HELD")
+
+	b = newBrowser(client, 2, "spending", Filter{Status: StatusSettled})
+	_, err = b.more()
+	require.NoError(t, err)
+	require.Contains(t, queries[1], "filter%5Bstatus%5D=SETTLED")
+
+	b = newBrowser(client, 2, "spending", Filter{Status: StatusAll})
+	_, err = b.more()
+	require.NoError(t, err)
+	require.NotContains(t, queries[2], "filter%5Bstatus%5D")
+}
+
+func TestDetails(t *testing.T) {
+	loc, err := time.LoadLocation("Australia/Melbourne")
+	require.NoError(t, err)
+
+	transaction := newTransaction("transaction-id", upngo.TransactionStatusHeld)
+	attributes := &transaction.Attributes
+	attributes.Description = "Coffee"
+	attributes.Amount = upngo.MoneyObject{CurrencyCode: "AUD", Value: "-4.50"}
+	attributes.ForeignAmount = upngo.MoneyObject{CurrencyCode: "USD", Value: "-3.00"}
+	attributes.HoldInfo.Amount = upngo.MoneyObject{CurrencyCode: "AUD", Value: "-5.00"}
+	attributes.RoundUp.Amount = upngo.MoneyObject{CurrencyCode: "AUD", Value: "-0.50"}
+	attributes.RoundUp.BoostPortion = upngo.MoneyObject{CurrencyCode: "AUD", Value: "-0.10"}
+	attributes.Cashback.Amount = upngo.MoneyObject{CurrencyCode: "AUD", Value: "1.00"}
+	attributes.Cashback.Description = "Promo"
+	attributes.CreatedAt = time.Date(2020, 8, 1, 23, 30, 0, 0, time.UTC)
+	transaction.Relationships.Account.Data.ID = "spending"
+	transaction.Relationships.Category.Data = &upngo.DataObject{Type: "categories", ID: "coffee"}
+	transaction.Relationships.ParentCategory.Data = &upngo.DataObject{Type: "categories", ID: "good-life"}
+	transaction.Relationships.Tags.Data = []upngo.DataObject{{Type: "tags", ID: "work"}, {Type: "tags", ID: "caffeine"}}
+
+	names := map[string]string{"spending": "Spending", "coffee": "Coffee", "good-life": "Good Life"}
+	require.Equal(t, strings.Join([]string{
+		"ID:             transaction-id",
+		"Description:    Coffee",
+		"Status:         HELD",
+		"Amount:         -4.50 AUD",
+		"Foreign amount: -3.00 USD",
+		"Held amount:    -5.00 AUD",
+		"Round up:       -0.50 AUD",
+		"Boost:          -0.10 AUD",
+		"Cashback:       1.00 AUD (Promo)",
+		"Created:        02/08/2020 09:30",
+		"Account:        Spending",
+		"Category:       Coffee (Good Life)",
+		"Tags:           caffeine, work",
+	}, "\n"), details(transaction, names, loc, "02/01/2006"))
+}
+
+func TestDiffTags(t *testing.T) {
+	added, removed := diffTags([]string{"work", "coffee"}, splitTags(" coffee, ,lunch,"))
+	require.Equal(t, []string{"lunch"}, added)
+	require.Equal(t, []string{"work"}, removed)
+}
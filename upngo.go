@@ -1,17 +1,17 @@
 package upngo
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -19,10 +19,86 @@ const (
 	MaxWebhookDescriptionLength = 300
 )
 
+// Client talks to the UpBank API. A Client is safe for concurrent use by
+// multiple goroutines, and should be reused rather than created per request so
+// that connections and the rate limiter are shared.
 type Client struct {
-	token   string
-	baseURL string
-	client  *http.Client
+	// Each group of endpoints has its own sub-client, e.g.
+	// `client.Transactions.List()`. They can be replaced, e.g. with
+	// `NewTransactionsClient` and the mock package, to test code that uses
+	// the client without a server.
+	Accounts     AccountsClient
+	Transactions TransactionsClient
+	Categories   CategoriesClient
+	Tags         TagsClient
+	Webhooks     WebhooksClient
+
+	token    string
+	baseURL  string
+	client   *http.Client
+	ctx      context.Context
+	logger   Logger
+	logLevel LogLevel
+
+	observer     Observer
+	tracer       Tracer
+	maxRetries   int
+	retryBackoff time.Duration
+	limiter      *rate.Limiter
+	middleware   []func(http.RoundTripper) http.RoundTripper
+}
+
+// WithContext returns a copy of the client that makes its requests with
+// `ctx`, so they can be cancelled or given a deadline. The copy shares its
+// connections and rate limiter with the original.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	copied := *c
+	copied.ctx = ctx
+	copied.initServices()
+	return &copied
+}
+
+// initServices points the client's services at it. It has to be done again
+// whenever the client is copied, otherwise the services would still send
+// requests with the original. Services that have been replaced with something
+// else, like a mock, are left alone.
+func (c *Client) initServices() {
+	if accounts, _ := c.Accounts.api(); c.Accounts == nil || isService(accounts) {
+		c.Accounts = NewAccountsClient(&AccountsService{client: c})
+	}
+	if transactions, _ := c.Transactions.api(); c.Transactions == nil || isService(transactions) {
+		c.Transactions = NewTransactionsClient(&TransactionsService{client: c})
+	}
+	if categories, _ := c.Categories.api(); c.Categories == nil || isService(categories) {
+		c.Categories = NewCategoriesClient(&CategoriesService{client: c})
+	}
+	if tags, _ := c.Tags.api(); c.Tags == nil || isService(tags) {
+		c.Tags = NewTagsClient(&TagsService{client: c})
+	}
+	if webhooks, _ := c.Webhooks.api(); c.Webhooks == nil || isService(webhooks) {
+		c.Webhooks = NewWebhooksClient(&WebhooksService{client: c})
+	}
+}
+
+// isService reports whether `api` is one of the client's own services rather
+// than something that's replaced it.
+func isService(api interface{}) bool {
+	switch api.(type) {
+	case *AccountsService, *TransactionsService, *CategoriesService, *TagsService, *WebhooksService:
+		return true
+	}
+	return false
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+func (c *Client) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(c.context(), method, url, body)
 }
 
 func (c *Client) buildURL(parts ...string) string {
@@ -30,223 +106,199 @@ func (c *Client) buildURL(parts ...string) string {
 	return fmt.Sprintf("%s/api/v1/%s", c.baseURL, endpoint)
 }
 
-func NewClient(token string) *Client {
-	httpClient := http.DefaultClient
-	var transport http.RoundTripper
-	transport = newAddAuthorizationHeaderTransport(httpClient.Transport, token)
-	transport = newLogTransport(transport)
-	httpClient.Transport = transport
-
-	return &Client{
-		token:   token,
-		baseURL: "https://api.up.com.au",
-		client:  http.DefaultClient,
+func NewClient(token string, options ...ClientOption) *Client {
+	c := &Client{
+		token:    token,
+		baseURL:  "https://api.up.com.au",
+		logLevel: LogOff,
 	}
+	for _, option := range options {
+		option(c)
+	}
+
+	// Each client gets its own http.Client so that clients with different
+	// tokens or loggers don't interfere with each other. The authorization
+	// header is added before logging so the logs show the request that's
+	// actually sent, with the token redacted. Each attempt at a request is
+	// logged, observed and rate limited but the whole request, retries and
+	// all, is one span.
+	var transport http.RoundTripper
+	transport = newLogTransport(http.DefaultTransport, c.logger, c.logLevel, token)
+	transport = newObserveTransport(transport, c.observer)
+	transport = newLimitTransport(transport, c.limiter)
+	transport = newRetryTransport(transport, c.maxRetries, c.retryBackoff)
+	for _, middleware := range c.middleware {
+		transport = middleware(transport)
+	}
+	transport = newTraceTransport(transport, c.tracer)
+	transport = newAddAuthorizationHeaderTransport(transport, token)
+	c.client = &http.Client{Transport: transport}
+	c.initServices()
+
+	return c
 }
 
 // Ping pings the UpBank API and returns an error if there is an problem.
 func (c *Client) Ping() error {
-	url := c.buildURL("util/ping")
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return err
-	}
+	_, err := c.PingWithResponse()
+	return err
+}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Read the body out here because in either case (error or happy), we're
-	// going to want the body. The only different will be in the structure we
-	// parse the JSON into.
-	respBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read ping body: %w", err)
-	}
+// PingWithResponse pings the UpBank API like `Ping` but also returns the
+// response, which includes the ID of the token's owner.
+func (c *Client) PingWithResponse() (PingResponse, error) {
+	var pingResponse PingResponse
+	err := c.do(request{
+		name:   "ping",
+		method: http.MethodGet,
+		url:    c.buildURL("util", "ping"),
+	}, &pingResponse)
+
+	// It doesn't say it but it looks like form the docs (and makes sense)
+	// that the ping `errors` field will always be of length 1. If this turns
+	// out to be wrong (by blowing up in my face :D) then we can just add a bit
+	// more detail here.
+	var responseErr *multierror.Error
+	if errors.As(err, &responseErr) && len(responseErr.Errors) > 0 {
+		return PingResponse{}, fmt.Errorf("ping failed: %s", responseErr.Errors[0])
+	}
+	return pingResponse, err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		var pingErrorResponse ErrorResponse
-		if err := unmarshal(respBody, &pingErrorResponse); err != nil {
-			return fmt.Errorf("failed to unmarshal ping error response: %w", err)
-		}
-		// It doesn't say it but it looks like form the docs (and makes sense)
-		// that the ping `errors` field will always be of length 1. If this
-		// turns out to be wrong (by blowing up in my face :D) then we can just
-		// add a bit more detail here.
-		return fmt.Errorf("ping failed: %s", pingErrorResponse.Errors[0].Detail)
-	}
+// AccountsOption is an option for listing accounts.
+type AccountsOption interface {
+	Option
+	accountsOption()
+}
 
-	var pingResponse PingResponse
-	if err := unmarshal(respBody, &pingResponse); err != nil {
-		return fmt.Errorf("failed to unmarshal ping response: %w", err)
-	}
+// TransactionsOption is an option for listing transactions, either all of
+// them or an account's.
+type TransactionsOption interface {
+	Option
+	transactionsOption()
+}
 
-	// Everything is okay so we just return nil.
-	return nil
+// CategoriesOption is an option for listing categories.
+type CategoriesOption interface {
+	Option
+	categoriesOption()
 }
 
-// AccountsOption represents an option (URL param) for the Accounts endpoint.
-//
-// This type isn't exposed so we instead expose constructors to build it with
-// the given values. Once it gets to the point that this struct is constructed,
-// the type of value doesn't really matter because it's just going into the URL
-// param so it's a string for convenience. It's the job of the constructor to
-// convert the given value to a string.
-//
-// For constructors, the `name` should be the key in the URL param and `value`
-// should be the value, i.e. options will be formatted as `<name>=<value>` in
-// the URL.
-type AccountsOption struct {
-	name  string
-	value string
+// TagsOption is an option for listing tags.
+type TagsOption interface {
+	Option
+	tagsOption()
 }
 
-// WithPageSize specifies that the API should return `size` number of accounts.
-func WithPageSize(size int) AccountsOption {
-	return AccountsOption{
-		// This is the key in the URL param that dictates the paging size. It's
-		// kind of weird, I've never seen URL params putting stuff in square
-		// brackets before but there you go.
-		name:  "page[size]",
-		value: strconv.Itoa(size),
-	}
+// WebhooksOption is an option for listing webhooks.
+type WebhooksOption interface {
+	Option
+	webhooksOption()
 }
 
-// Accounts lists all the accounts associated with the authenticated account.
-func (c *Client) Accounts(options ...AccountsOption) (AccountsResponse, error) {
-	url := c.buildURL("accounts")
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return AccountsResponse{}, fmt.Errorf("failed to get accounts request: %w", err)
-	}
+// WebhookLogsOption is an option for listing a webhook's delivery logs.
+type WebhookLogsOption interface {
+	Option
+	webhookLogsOption()
+}
 
-	query := req.URL.Query()
-	for _, option := range options {
-		// Using `Add`, not `Set` is important because it meanst that if and
-		// option is supplied twice then both values are included in the query.
-		query.Add(option.name, option.value)
-	}
-	req.URL.RawQuery = query.Encode()
+// RegisterWebhookOption is an option for registering a webhook.
+type RegisterWebhookOption interface {
+	Option
+	registerWebhookOption()
+}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return AccountsResponse{}, fmt.Errorf("failed to get accounts: %w", err)
-	}
-	defer resp.Body.Close()
+// PageSizeOption sets how many results are in each page. It's an option for
+// every endpoint that returns results a page at a time.
+type PageSizeOption struct {
+	option
+}
 
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return AccountsResponse{}, fmt.Errorf("failed to read account response body: %w", err)
-	}
+func (PageSizeOption) accountsOption()     {}
+func (PageSizeOption) transactionsOption() {}
+func (PageSizeOption) tagsOption()         {}
+func (PageSizeOption) webhooksOption()     {}
+func (PageSizeOption) webhookLogsOption()  {}
 
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse ErrorResponse
-		if err := unmarshal(responseBody, &errorResponse); err != nil {
-			return AccountsResponse{}, fmt.Errorf("failed to unmarshall get accounts error: %w", err)
-		}
-
-		var err error
-		for _, responseError := range errorResponse.Errors {
-			err = multierror.Append(
-				err,
-				errors.New(responseError.Detail),
-			)
-		}
-		return AccountsResponse{}, err
-	}
+type transactionsFilter struct {
+	option
+}
 
-	var accountsResponse AccountsResponse
-	if err := unmarshal(responseBody, &accountsResponse); err != nil {
-		return AccountsResponse{}, fmt.Errorf("failed to unmarshal get accounts response: %w", err)
-	}
+func (transactionsFilter) transactionsOption() {}
 
-	return accountsResponse, nil
+type categoriesFilter struct {
+	option
 }
 
-// TransactionsOption is an option for the transactions API.
-type TransactionsOption struct {
-	name  string
-	value string
+func (categoriesFilter) categoriesOption() {}
+
+type webhookDescription struct {
+	option
 }
 
-// TODO: This is a horrible name but otherwise it clashes with the accounts page
-// size option. Anyway, I'm thinking either have to test out whether we can have
-// a base option then alias it for the specific ones but I'm not sure if that
-// will still be typesafe. Alternatively, it might be good, in general, to split
-// out each resoure into a subclient, then they could have different namespaces.
-func WithTransactionPageSize(size int) TransactionsOption {
-	return TransactionsOption{
+func (webhookDescription) registerWebhookOption() {}
+
+// WithPageSize specifies that the API should return `size` number of results
+// per page.
+func WithPageSize(size int) PageSizeOption {
+	return PageSizeOption{option{
+		// This is the key in the URL param that dictates the paging size. It's
+		// kind of weird, I've never seen URL params putting stuff in square
+		// brackets before but there you go.
 		name:  "page[size]",
 		value: strconv.Itoa(size),
-	}
+	}}
+}
+
+// WithTransactionPageSize specifies that the API should return `size` number
+// of transactions.
+//
+// Deprecated: Use WithPageSize, which works for every endpoint that returns
+// results a page at a time.
+func WithTransactionPageSize(size int) TransactionsOption {
+	return WithPageSize(size)
 }
 
+// WithFilterSince limits transactions to those created at or after `since`.
 func WithFilterSince(since time.Time) TransactionsOption {
-	return TransactionsOption{
+	return transactionsFilter{option{
 		name:  "filter[since]",
 		value: since.Format(time.RFC3339),
-	}
+	}}
 }
 
+// WithFilterUntil limits transactions to those created before `until`.
 func WithFilterUntil(until time.Time) TransactionsOption {
-	return TransactionsOption{
+	return transactionsFilter{option{
 		name:  "filter[until]",
 		value: until.Format(time.RFC3339),
-	}
+	}}
 }
 
-// Transactions lists all the transactions associated with the authenticated
-// account.
-func (c *Client) Transactions(options ...TransactionsOption) (TransactionsResponse, error) {
-	url := c.buildURL("transactions")
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return TransactionsResponse{}, fmt.Errorf("failed to create transactions request: %w", err)
-	}
-
-	query := req.URL.Query()
-	for _, option := range options {
-		// Using `Add`, not `Set` is important because it meanst that if and
-		// option is supplied twice then both values are included in the query.
-		query.Add(option.name, option.value)
-	}
-	req.URL.RawQuery = query.Encode()
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return TransactionsResponse{}, fmt.Errorf("failed to get transactions: %w", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return TransactionsResponse{}, fmt.Errorf("failed to read transactions response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse ErrorResponse
-		if err := unmarshal(responseBody, &errorResponse); err != nil {
-			return TransactionsResponse{}, fmt.Errorf("failed to unmarshall get transactions error: %w", err)
-		}
-
-		var err error
-		for _, responseError := range errorResponse.Errors {
-			err = multierror.Append(
-				err,
-				errors.New(responseError.Detail),
-			)
-		}
-		return TransactionsResponse{}, err
-	}
+// WithFilterStatus limits transactions to those with the given status, e.g.
+// only the ones that are still held.
+func WithFilterStatus(status TransactionStatus) TransactionsOption {
+	return transactionsFilter{option{
+		name:  "filter[status]",
+		value: string(status),
+	}}
+}
 
-	var transactionsResponse TransactionsResponse
-	if err := unmarshal(responseBody, &transactionsResponse); err != nil {
-		return TransactionsResponse{}, fmt.Errorf("failed to unmarshal get transactions response: %w", err)
-	}
+// WithFilterParent limits categories to the children of the category with the
+// given ID.
+func WithFilterParent(categoryID string) CategoriesOption {
+	return categoriesFilter{option{
+		name:  "filter[parent]",
+		value: categoryID,
+	}}
+}
 
-	return transactionsResponse, nil
+// WithDescription gives a webhook a description when it's registered.
+func WithDescription(desc string) RegisterWebhookOption {
+	return webhookDescription{option{
+		name:  "description",
+		value: desc,
+	}}
 }
 
 func unmarshalToErr(response []byte) error {
@@ -263,176 +315,81 @@ func unmarshalToErr(response []byte) error {
 	return err
 }
 
+// NextAccounts retrieves the page of accounts after the given response.
+//
+// Deprecated: Use Accounts.Next.
+func (c *Client) NextAccounts(accounts AccountsResponse) (AccountsResponse, error) {
+	return c.Accounts.Next(accounts)
+}
+
 // Account retrieves an account by its ID.
+//
+// Deprecated: Use Accounts.Get.
 func (c *Client) Account(id string) (AccountResponse, error) {
-	url := c.buildURL("accounts", id)
-	resp, err := c.client.Get(url)
-	if err != nil {
-		return AccountResponse{}, fmt.Errorf("failed to send get account by ID request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return AccountResponse{}, fmt.Errorf("failed to read get account by ID response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		err = unmarshalToErr(responseBody)
-		return AccountResponse{}, err
-	}
+	return c.Accounts.Get(id)
+}
 
-	var accountResponse AccountResponse
-	if err := unmarshal(responseBody, &accountResponse); err != nil {
-		return AccountResponse{}, fmt.Errorf("failed to unmarshal get account by ID response: %w", err)
-	}
+// AccountTransactions lists the transactions for the account with the given
+// ID.
+//
+// Deprecated: Use Accounts.Transactions.
+func (c *Client) AccountTransactions(accountID string, options ...TransactionsOption) (TransactionsResponse, error) {
+	return c.Accounts.Transactions(accountID, options...)
+}
 
-	return accountResponse, nil
+// NextTransactions retrieves the page of transactions after the given
+// response.
+//
+// Deprecated: Use Transactions.Next.
+func (c *Client) NextTransactions(transactions TransactionsResponse) (TransactionsResponse, error) {
+	return c.Transactions.Next(transactions)
 }
 
 // Transaction retrieves a transaction by its ID.
+//
+// Deprecated: Use Transactions.Get.
 func (c *Client) Transaction(id string) (TransactionResponse, error) {
-	url := c.buildURL("transactions", id)
-	resp, err := c.client.Get(url)
-	if err != nil {
-		return TransactionResponse{}, fmt.Errorf("failed to send get transaction by ID request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return TransactionResponse{}, fmt.Errorf("failed to read get transaction by ID response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		err = unmarshalToErr(responseBody)
-		return TransactionResponse{}, err
-	}
-
-	var transactionResponse TransactionResponse
-	if err := unmarshal(responseBody, &transactionResponse); err != nil {
-		return TransactionResponse{}, fmt.Errorf("failed to unmarshal get transaction by ID response: %w", err)
-	}
-
-	return transactionResponse, nil
+	return c.Transactions.Get(id)
 }
 
-// Webhooks gets all the webhooks.
-func (c *Client) Webhooks() (WebhooksResponse, error) {
-	url := c.buildURL("webhooks")
-	resp, err := c.client.Get(url)
-	if err != nil {
-		return WebhooksResponse{}, fmt.Errorf("failed to send get webhooks request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return WebhooksResponse{}, fmt.Errorf("failed to read get webhooks response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		err = unmarshalToErr(responseBody)
-		return WebhooksResponse{}, err
-	}
+// Categorize sets the category of the transaction.
+//
+// Deprecated: Use Transactions.Categorize.
+func (c *Client) Categorize(transactionID string, categoryID string) error {
+	return c.Transactions.Categorize(transactionID, categoryID)
+}
 
-	var webhooksResponse WebhooksResponse
-	if err := unmarshal(responseBody, &webhooksResponse); err != nil {
-		return WebhooksResponse{}, fmt.Errorf("failed to unmarshal webhooks response: %w", err)
-	}
-	return webhooksResponse, nil
+// AddTags adds the tags to the transaction.
+//
+// Deprecated: Use Transactions.Tag.
+func (c *Client) AddTags(transactionID string, tags ...string) error {
+	return c.Transactions.Tag(transactionID, tags...)
 }
 
-type RegisterWebhookOption struct {
-	name  string
-	value string
+// RemoveTags removes the tags from the transaction.
+//
+// Deprecated: Use Transactions.Untag.
+func (c *Client) RemoveTags(transactionID string, tags ...string) error {
+	return c.Transactions.Untag(transactionID, tags...)
 }
 
-func WithDescription(desc string) RegisterWebhookOption {
-	return RegisterWebhookOption{
-		name:  "description",
-		value: desc,
-	}
+// NextTags retrieves the page of tags after the given response.
+//
+// Deprecated: Use Tags.Next.
+func (c *Client) NextTags(tags TagsResponse) (TagsResponse, error) {
+	return c.Tags.Next(tags)
 }
 
 // RegisterWebhook registers a webhook for the given URL.
+//
+// Deprecated: Use Webhooks.Create.
 func (c *Client) RegisterWebhook(webhookURL string, opts ...RegisterWebhookOption) (WebhookResponse, error) {
-	url := c.buildURL("webhooks")
-	var description string
-	for _, opt := range opts {
-		if opt.name == "description" {
-			description = opt.value
-		}
-	}
-
-	if len(webhookURL) > MaxWebhookURLLength {
-		return WebhookResponse{}, fmt.Errorf("webhook URL too long. Max length is %d", MaxWebhookURLLength)
-	}
-
-	if len(description) > MaxWebhookDescriptionLength {
-		return WebhookResponse{}, fmt.Errorf("webhook description too long. Max length is %d", MaxWebhookDescriptionLength)
-	}
-
-	input := RegisterWebhookRequest{
-		Data: WebhookInputResource{
-			Attributes: WebhookInputResourceAttributes{
-				URL:         webhookURL,
-				Description: description,
-			},
-		},
-	}
-	requestBody, err := json.Marshal(input)
-	if err != nil {
-		// Something's really goofed up here.
-		return WebhookResponse{}, fmt.Errorf("failed to marshal request body (this should never happen): %w", err)
-	}
-
-	resp, err := c.client.Post(url, "application/json", bytes.NewBuffer(requestBody))
-	if err != nil {
-		return WebhookResponse{}, fmt.Errorf("request to register webhook failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return WebhookResponse{}, fmt.Errorf("failed to read register webhook response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusCreated {
-		fmt.Println(string(responseBody))
-		err = unmarshalToErr(responseBody)
-		return WebhookResponse{}, err
-	}
-
-	var webhookResponse WebhookResponse
-	if err := unmarshal(responseBody, &webhookResponse); err != nil {
-		return WebhookResponse{}, fmt.Errorf("failed to unmarshall register webhook response body: %w", err)
-	}
-
-	return webhookResponse, nil
+	return c.Webhooks.Create(webhookURL, opts...)
 }
 
-func (c Client) PingWebhook(id string) (WebhookPingResponse, error) {
-	url := c.buildURL("webhooks", id, "ping")
-	resp, err := c.client.Post(url, "application/json", nil)
-	if err != nil {
-		return WebhookPingResponse{}, fmt.Errorf("failed to send ping request to webhook: %w", err)
-	}
-	defer resp.Body.Close()
-
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return WebhookPingResponse{}, fmt.Errorf("failed to read webhook ping response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusCreated {
-		err = unmarshalToErr(responseBody)
-		return WebhookPingResponse{}, err
-	}
-
-	var webhookPingResponse WebhookPingResponse
-	if err := unmarshal(responseBody, &webhookPingResponse); err != nil {
-		return WebhookPingResponse{}, fmt.Errorf("failed to unmarshal webhook ping response: %w", err)
-	}
-	return webhookPingResponse, nil
+// PingWebhook sends a ping event to the webhook with the given ID.
+//
+// Deprecated: Use Webhooks.Ping.
+func (c *Client) PingWebhook(id string) (WebhookPingResponse, error) {
+	return c.Webhooks.Ping(id)
 }
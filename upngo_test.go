@@ -1,11 +1,17 @@
 package upngo
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -70,6 +76,22 @@ func TestPingOk(t *testing.T) {
 	require.NoError(t, client.Ping())
 }
 
+func TestPingWithResponse(t *testing.T) {
+	token := "token"
+	expectedResponse := PingResponse{
+		Meta: PingResponseMeta{
+			ID:          "c0ee698b-6707-4d87-a1b3-80393f1f8571",
+			StatusEmoji: "⚡️",
+		},
+	}
+	server, client := newServerClientForURL(t, token, "/api/v1/util/ping", http.StatusOK, expectedResponse)
+	defer server.Close()
+
+	response, err := client.PingWithResponse()
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, response)
+}
+
 func TestPingErr(t *testing.T) {
 	token := "token"
 	detail := "The request was not authenticated because no valid credential was found in the Authorization header, or the Authorization header was not present."
@@ -510,3 +532,916 @@ func TestTransactionsMultipleError(t *testing.T) {
 	expectedErr = multierror.Append(expectedErr, errors.New(detail2))
 	require.Equal(t, expectedErr, err)
 }
+
+func TestAccountTransactions(t *testing.T) {
+	expectedResponse := TransactionsResponse{
+		Data: []TransactionResource{
+			{
+				Resource: Resource{
+					ID:   "id",
+					Type: "transactions",
+				},
+			},
+		},
+	}
+	token := "token"
+	server, client := newServerClientForURL(
+		t,
+		token,
+		"/api/v1/accounts/account-id/transactions",
+		http.StatusOK,
+		expectedResponse,
+		func(req *http.Request) {
+			require.Equal(t, "10", req.URL.Query().Get("page[size]"))
+		},
+	)
+	defer server.Close()
+	transactions, err := client.AccountTransactions("account-id", WithTransactionPageSize(10))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, transactions)
+}
+
+func TestNextTransactions(t *testing.T) {
+	expectedResponse := TransactionsResponse{
+		Data: []TransactionResource{
+			{
+				Resource: Resource{
+					ID:   "id",
+					Type: "transactions",
+				},
+			},
+		},
+	}
+	token := "token"
+	server, client := newServerClientForURL(
+		t,
+		token,
+		"/api/v1/transactions",
+		http.StatusOK,
+		expectedResponse,
+		func(req *http.Request) {
+			require.Equal(t, "cursor", req.URL.Query().Get("page[after]"))
+		},
+	)
+	defer server.Close()
+
+	page := TransactionsResponse{
+		Links: LinksObject{
+			Next: server.URL + "/api/v1/transactions?page%5Bafter%5D=cursor",
+		},
+	}
+	transactions, err := client.NextTransactions(page)
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, transactions)
+
+	_, err = client.NextTransactions(transactions)
+	require.True(t, errors.Is(err, ErrNoNextPage))
+}
+
+func TestCategories(t *testing.T) {
+	expectedResponse := CategoriesResponse{
+		Data: []CategoryResource{
+			{
+				Type: "categories",
+				ID:   "hobbies",
+				Attributes: CategoryAttributes{
+					Name: "Hobbies",
+				},
+				Relationships: CategoryRelationships{
+					Parent: OptionalRelationshipObject{
+						Data: &DataObject{
+							Type: "categories",
+							ID:   "good-life",
+						},
+					},
+				},
+			},
+		},
+	}
+	token := "token"
+	server, client := newServerClientForURL(t, token, "/api/v1/categories", http.StatusOK, expectedResponse)
+	defer server.Close()
+	categories, err := client.Categories()
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, categories)
+}
+
+func TestTags(t *testing.T) {
+	expectedResponse := TagsResponse{
+		Data: []TagResource{
+			{
+				Type: "tags",
+				ID:   "Holiday",
+			},
+		},
+	}
+	token := "token"
+	server, client := newServerClientForURL(t, token, "/api/v1/tags", http.StatusOK, expectedResponse)
+	defer server.Close()
+	tags, err := client.Tags()
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, tags)
+
+	_, err = client.NextTags(tags)
+	require.True(t, errors.Is(err, ErrNoNextPage))
+}
+
+func TestFormatBaseUnits(t *testing.T) {
+	require.Equal(t, "0.05", FormatBaseUnits(5, "AUD"))
+	require.Equal(t, "-123.45", FormatBaseUnits(-12345, "AUD"))
+	require.Equal(t, "500", FormatBaseUnits(500, "JPY"))
+}
+
+func TestParseBaseUnits(t *testing.T) {
+	for value, expected := range map[string]int64{
+		"12":     1200,
+		"12.3":   1230,
+		"-12.34": -1234,
+		".5":     50,
+	} {
+		baseUnits, err := ParseBaseUnits(value, "AUD")
+		require.NoError(t, err)
+		require.Equal(t, expected, baseUnits, value)
+	}
+
+	_, err := ParseBaseUnits("1.234", "AUD")
+	require.Error(t, err)
+	_, err = ParseBaseUnits("lots", "AUD")
+	require.Error(t, err)
+	for _, value := range []string{"", ".", "-", "-."} {
+		_, err = ParseBaseUnits(value, "AUD")
+		require.Error(t, err, value)
+	}
+}
+
+// newNoContentServerClient creates a server that responds with no content and
+// a client that talks to it. The request body is decoded into `body`.
+func newNoContentServerClient(t *testing.T, method string, url string, body interface{}) (*httptest.Server, *Client) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, method, req.Method)
+		require.Equal(t, url, req.URL.Path)
+		require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(req.Body).Decode(body))
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	client := NewClient("token")
+	client.baseURL = server.URL
+	oldTransport := client.client.Transport
+	client.client = server.Client()
+	client.client.Transport = oldTransport
+	return server, client
+}
+
+func TestCategorize(t *testing.T) {
+	var body RelationshipRequest
+	server, client := newNoContentServerClient(t, http.MethodPatch, "/api/v1/transactions/id/relationships/category", &body)
+	defer server.Close()
+
+	require.NoError(t, client.Categorize("id", "takeaway"))
+	require.Equal(t, RelationshipRequest{Data: &DataObject{Type: "categories", ID: "takeaway"}}, body)
+
+	require.NoError(t, client.Categorize("id", ""))
+	require.Equal(t, RelationshipRequest{}, body)
+}
+
+func TestAddTags(t *testing.T) {
+	var body RelationshipsRequest
+	server, client := newNoContentServerClient(t, http.MethodPost, "/api/v1/transactions/id/relationships/tags", &body)
+	defer server.Close()
+
+	require.NoError(t, client.AddTags("id", "Holiday", "Work"))
+	require.Equal(t, RelationshipsRequest{Data: []DataObject{{Type: "tags", ID: "Holiday"}, {Type: "tags", ID: "Work"}}}, body)
+}
+
+func TestRemoveTags(t *testing.T) {
+	var body RelationshipsRequest
+	server, client := newNoContentServerClient(t, http.MethodDelete, "/api/v1/transactions/id/relationships/tags", &body)
+	defer server.Close()
+
+	require.NoError(t, client.RemoveTags("id", "Holiday"))
+	require.Equal(t, RelationshipsRequest{Data: []DataObject{{Type: "tags", ID: "Holiday"}}}, body)
+}
+
+func TestCategorizeError(t *testing.T) {
+	detail := "transfers can't be categorized"
+	expectedResponse := ErrorResponse{
+		Errors: []ErrorObject{
+			{
+				Status: "422",
+				Title:  "title",
+				Detail: detail,
+			},
+		},
+	}
+	server, client := newServerClientForURL(
+		t,
+		"token",
+		"/api/v1/transactions/id/relationships/category",
+		http.StatusUnprocessableEntity,
+		expectedResponse,
+	)
+	defer server.Close()
+
+	err := client.Categorize("id", "takeaway")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), detail)
+}
+
+func TestRegisterWebhook(t *testing.T) {
+	token := "token"
+	expectedResponse := WebhookResponse{
+		Data: WebhookResource{
+			ID:         "webhook-id",
+			Attributes: WebhooksAttributes{URL: "https://example.com", Description: "hook", SecretKey: "secret"},
+		},
+	}
+	server, client := newServerClientForURL(t, token, "/api/v1/webhooks", http.StatusCreated, expectedResponse, func(req *http.Request) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+		var body RegisterWebhookRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		require.Equal(t, "https://example.com", body.Data.Attributes.URL)
+		require.Equal(t, "hook", body.Data.Attributes.Description)
+	})
+	defer server.Close()
+
+	actualResponse, err := client.RegisterWebhook("https://example.com", WithDescription("hook"))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, actualResponse)
+}
+
+func TestRegisterWebhookTooLong(t *testing.T) {
+	client := NewClient("token")
+	_, err := client.RegisterWebhook(strings.Repeat("a", MaxWebhookURLLength+1))
+	require.Error(t, err)
+}
+
+func TestWebhooksGet(t *testing.T) {
+	expectedResponse := WebhookResponse{Data: WebhookResource{ID: "webhook-id"}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/webhooks/webhook-id", http.StatusOK, expectedResponse)
+	defer server.Close()
+
+	actualResponse, err := client.Webhooks.Get("webhook-id")
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, actualResponse)
+}
+
+func TestWebhooksDelete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/api/v1/webhooks/webhook-id", req.URL.Path)
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL))
+	require.NoError(t, client.Webhooks.Delete("webhook-id"))
+}
+
+func TestWebhooksLogs(t *testing.T) {
+	var log WebhookDeliveryLogResource
+	log.ID = "log-id"
+	log.Attributes.DeliveryStatus = WebhookDeliveryStatusBadResponseCode
+	log.Attributes.Response = &WebhookDeliveryLogResponse{StatusCode: 500, Body: "oops"}
+	expectedResponse := WebhookLogsResponse{Data: []WebhookDeliveryLogResource{log}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/webhooks/webhook-id/logs", http.StatusOK, expectedResponse, func(req *http.Request) {
+		require.Equal(t, "10", req.URL.Query().Get("page[size]"))
+	})
+	defer server.Close()
+
+	actualResponse, err := client.Webhooks.Logs("webhook-id", WithPageSize(10))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, actualResponse)
+
+	_, err = client.Webhooks.NextLogs(actualResponse)
+	require.Equal(t, ErrNoNextPage, err)
+}
+
+func TestCategoriesGet(t *testing.T) {
+	expectedResponse := CategoryResponse{Data: CategoryResource{ID: "takeaway"}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/categories/takeaway", http.StatusOK, expectedResponse)
+	defer server.Close()
+
+	actualResponse, err := client.Categories.Get("takeaway")
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, actualResponse)
+}
+
+func TestAccountsService(t *testing.T) {
+	expectedResponse := AccountsResponse{Data: []AccountResource{{ID: "account-id"}}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/accounts", http.StatusOK, expectedResponse, func(req *http.Request) {
+		require.Equal(t, "10", req.URL.Query().Get("page[size]"))
+	})
+	defer server.Close()
+
+	accounts, err := client.Accounts.List(WithPageSize(10))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, accounts)
+
+	_, err = client.Accounts.Next(accounts)
+	require.True(t, errors.Is(err, ErrNoNextPage))
+}
+
+func TestAccountsGet(t *testing.T) {
+	expectedResponse := AccountResponse{Data: AccountResource{ID: "account-id"}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/accounts/account-id", http.StatusOK, expectedResponse)
+	defer server.Close()
+
+	account, err := client.Accounts.Get("account-id")
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, account)
+}
+
+func TestAccountsTransactions(t *testing.T) {
+	since := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	expectedResponse := TransactionsResponse{Data: []TransactionResource{{Resource: Resource{ID: "transaction-id"}}}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/accounts/account-id/transactions", http.StatusOK, expectedResponse, func(req *http.Request) {
+		require.Equal(t, "10", req.URL.Query().Get("page[size]"))
+		require.Equal(t, "2020-08-01T00:00:00Z", req.URL.Query().Get("filter[since]"))
+	})
+	defer server.Close()
+
+	transactions, err := client.Accounts.Transactions("account-id", WithPageSize(10), WithFilterSince(since))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, transactions)
+}
+
+func TestTransactionsService(t *testing.T) {
+	until := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	expectedResponse := TransactionsResponse{Data: []TransactionResource{{Resource: Resource{ID: "transaction-id"}}}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/transactions", http.StatusOK, expectedResponse, func(req *http.Request) {
+		require.Equal(t, "10", req.URL.Query().Get("page[size]"))
+		require.Equal(t, "2020-09-01T00:00:00Z", req.URL.Query().Get("filter[until]"))
+		require.Equal(t, "SETTLED", req.URL.Query().Get("filter[status]"))
+	})
+	defer server.Close()
+
+	transactions, err := client.Transactions.List(WithPageSize(10), WithFilterUntil(until), WithFilterStatus(TransactionStatusSettled))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, transactions)
+
+	_, err = client.Transactions.Next(transactions)
+	require.True(t, errors.Is(err, ErrNoNextPage))
+}
+
+func TestTransactionsGet(t *testing.T) {
+	expectedResponse := TransactionResponse{Data: TransactionResource{Resource: Resource{ID: "transaction-id"}}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/transactions/transaction-id", http.StatusOK, expectedResponse)
+	defer server.Close()
+
+	transaction, err := client.Transactions.Get("transaction-id")
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, transaction)
+}
+
+func TestTransactionsCategorize(t *testing.T) {
+	var body RelationshipRequest
+	server, client := newNoContentServerClient(t, http.MethodPatch, "/api/v1/transactions/id/relationships/category", &body)
+	defer server.Close()
+
+	require.NoError(t, client.Transactions.Categorize("id", "takeaway"))
+	require.Equal(t, RelationshipRequest{Data: &DataObject{Type: "categories", ID: "takeaway"}}, body)
+}
+
+func TestTransactionsTag(t *testing.T) {
+	var body RelationshipsRequest
+	server, client := newNoContentServerClient(t, http.MethodPost, "/api/v1/transactions/id/relationships/tags", &body)
+	defer server.Close()
+
+	require.NoError(t, client.Transactions.Tag("id", "Holiday"))
+	require.Equal(t, RelationshipsRequest{Data: []DataObject{{Type: "tags", ID: "Holiday"}}}, body)
+}
+
+func TestTransactionsUntag(t *testing.T) {
+	var body RelationshipsRequest
+	server, client := newNoContentServerClient(t, http.MethodDelete, "/api/v1/transactions/id/relationships/tags", &body)
+	defer server.Close()
+
+	require.NoError(t, client.Transactions.Untag("id", "Holiday"))
+	require.Equal(t, RelationshipsRequest{Data: []DataObject{{Type: "tags", ID: "Holiday"}}}, body)
+}
+
+func TestCategoriesService(t *testing.T) {
+	expectedResponse := CategoriesResponse{Data: []CategoryResource{{ID: "takeaway"}}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/categories", http.StatusOK, expectedResponse, func(req *http.Request) {
+		require.Equal(t, "good-life", req.URL.Query().Get("filter[parent]"))
+	})
+	defer server.Close()
+
+	categories, err := client.Categories.List(WithFilterParent("good-life"))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, categories)
+}
+
+func TestTagsService(t *testing.T) {
+	expectedResponse := TagsResponse{Data: []TagResource{{Type: "tags", ID: "Holiday"}}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/tags", http.StatusOK, expectedResponse, func(req *http.Request) {
+		require.Equal(t, "10", req.URL.Query().Get("page[size]"))
+	})
+	defer server.Close()
+
+	tags, err := client.Tags.List(WithPageSize(10))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, tags)
+
+	_, err = client.Tags.Next(tags)
+	require.True(t, errors.Is(err, ErrNoNextPage))
+}
+
+func TestWebhooksService(t *testing.T) {
+	expectedResponse := WebhooksResponse{Data: []WebhookResource{{ID: "webhook-id"}}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/webhooks", http.StatusOK, expectedResponse, func(req *http.Request) {
+		require.Equal(t, "10", req.URL.Query().Get("page[size]"))
+	})
+	defer server.Close()
+
+	webhooks, err := client.Webhooks.List(WithPageSize(10))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, webhooks)
+
+	_, err = client.Webhooks.Next(webhooks)
+	require.True(t, errors.Is(err, ErrNoNextPage))
+}
+
+func TestWebhooksCreate(t *testing.T) {
+	expectedResponse := WebhookResponse{Data: WebhookResource{ID: "webhook-id"}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/webhooks", http.StatusCreated, expectedResponse, func(req *http.Request) {
+		var body RegisterWebhookRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		require.Equal(t, "https://example.com", body.Data.Attributes.URL)
+		require.Equal(t, "hook", body.Data.Attributes.Description)
+	})
+	defer server.Close()
+
+	webhook, err := client.Webhooks.Create("https://example.com", WithDescription("hook"))
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, webhook)
+
+	_, err = client.Webhooks.Create(strings.Repeat("a", MaxWebhookURLLength+1))
+	require.Error(t, err)
+}
+
+func TestDeprecatedMethods(t *testing.T) {
+	expectedResponse := AccountResponse{Data: AccountResource{ID: "id"}}
+	server, client := newServerClientForURL(t, "token", "/api/v1/accounts/id", http.StatusOK, expectedResponse)
+	defer server.Close()
+
+	actualResponse, err := client.Account("id")
+	require.NoError(t, err)
+	require.Equal(t, expectedResponse, actualResponse)
+}
+
+func newLoggingClient(t *testing.T, token string, level LogLevel, handler http.HandlerFunc) (*httptest.Server, *Client, *bytes.Buffer) {
+	server := httptest.NewServer(handler)
+	var logs bytes.Buffer
+	client := NewClient(token, WithLogger(log.New(&logs, "", 0), level))
+	client.baseURL = server.URL
+	return server, client, &logs
+}
+
+func TestLoggingRequests(t *testing.T) {
+	server, client, logs := newLoggingClient(t, "secret-token", LogRequests, func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`{"meta":{"id":"id","statusEmoji":"⚡️"}}`))
+	})
+	defer server.Close()
+
+	require.NoError(t, client.Ping())
+	require.Equal(t, fmt.Sprintf(
+		"--> GET %[1]s/api/v1/util/ping\n<-- 200 OK %[1]s/api/v1/util/ping\n",
+		server.URL,
+	), logs.String())
+}
+
+func TestLoggingRedacts(t *testing.T) {
+	token := "secret-token"
+	server, client, logs := newLoggingClient(t, token, LogBodies, func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "Bearer "+token, req.Header.Get("Authorization"))
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`{"data":[{"type":"webhooks","id":"id","attributes":{"url":"https://example.com","description":"","secretKey":"webhook-secret","createdAt":"2020-08-02T15:20:22Z"},"relationships":{"logs":{"links":{"related":"https://logs"}}},"links":{"self":"https://self"}}],"links":{"prev":null,"next":null}}`))
+	})
+	defer server.Close()
+
+	webhooks, err := client.Webhooks()
+	require.NoError(t, err)
+	// Logging the body mustn't stop us from reading it.
+	require.Equal(t, "webhook-secret", webhooks.Data[0].Attributes.SecretKey)
+
+	require.Contains(t, logs.String(), "--> Authorization: [REDACTED]")
+	require.Contains(t, logs.String(), "<-- Content-Type: application/json")
+	require.Contains(t, logs.String(), `"secretKey":"[REDACTED]"`)
+	require.NotContains(t, logs.String(), token)
+	require.NotContains(t, logs.String(), "webhook-secret")
+}
+
+func TestLoggingBodyReadError(t *testing.T) {
+	// The body is cut off before it's as long as the server said it would be.
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Length", "100")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte("{"))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	transport := newLogTransport(nil, log.New(&logs, "", 0), LogBodies, "token")
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+func TestLoggingOff(t *testing.T) {
+	server, client, logs := newLoggingClient(t, "token", LogOff, func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`{"meta":{"id":"id","statusEmoji":"⚡️"}}`))
+	})
+	defer server.Close()
+
+	require.NoError(t, client.Ping())
+	require.Empty(t, logs.String())
+}
+
+type recordingSpan struct {
+	name       string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *recordingSpan) RecordError(err error)                      { s.err = err }
+func (s *recordingSpan) End()                                       { s.ended = true }
+
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{name: name, attributes: make(map[string]interface{})}
+	r.spans = append(r.spans, span)
+	return ctx, span
+}
+
+func TestRetriesObservedAndTraced(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = rw.Write([]byte(`{"errors":[{"status":"503","title":"Unavailable","detail":"try again"}]}`))
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`{"meta":{"id":"id","statusEmoji":"⚡️"}}`))
+	}))
+	defer server.Close()
+
+	var observed []RequestInfo
+	tracer := &recordingTracer{}
+	client := NewClient(
+		"token",
+		WithRetries(2, time.Millisecond),
+		WithObserver(ObserverFunc(func(info RequestInfo) { observed = append(observed, info) })),
+		WithTracer(tracer),
+	)
+	client.baseURL = server.URL
+
+	require.NoError(t, client.Ping())
+	require.Equal(t, 2, calls)
+
+	require.Len(t, observed, 2)
+	require.Equal(t, "/util/ping", observed[0].Endpoint)
+	require.Equal(t, http.MethodGet, observed[0].Method)
+	require.Equal(t, http.StatusServiceUnavailable, observed[0].StatusCode)
+	require.Equal(t, 1, observed[0].Attempt)
+	require.Equal(t, http.StatusOK, observed[1].StatusCode)
+	require.Equal(t, 2, observed[1].Attempt)
+
+	// One span covers both attempts.
+	require.Len(t, tracer.spans, 1)
+	require.Equal(t, "upngo GET /util/ping", tracer.spans[0].name)
+	require.Equal(t, http.StatusOK, tracer.spans[0].attributes["http.status_code"])
+	require.True(t, tracer.spans[0].ended)
+}
+
+func TestRetriesGiveUp(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.Header().Set("Retry-After", "0")
+		rw.WriteHeader(http.StatusTooManyRequests)
+		_, _ = rw.Write([]byte(`{"errors":[{"status":"429","title":"Too Many Requests","detail":"slow down"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithRetries(2, time.Hour))
+	client.baseURL = server.URL
+
+	require.EqualError(t, client.Ping(), "ping failed: slow down")
+	require.Equal(t, 3, calls)
+
+	// Adding tags isn't idempotent so it's never retried.
+	calls = 0
+	require.Error(t, client.AddTags("id", "tag"))
+	require.Equal(t, 1, calls)
+}
+
+func TestEndpoint(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/api/v1/accounts":                                "/accounts",
+		"/api/v1/accounts/abc-123/transactions":           "/accounts/{id}/transactions",
+		"/api/v1/transactions/abc/relationships/category": "/transactions/{id}/relationships/category",
+		"/api/v1/webhooks/abc/ping":                       "/webhooks/{id}/ping",
+		"/api/v1/categories/good-life":                    "/categories/{id}",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		require.Equal(t, expected, Endpoint(req), path)
+	}
+}
+
+func newTransactionServer(t *testing.T, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(calls, 1)
+		id := strings.TrimPrefix(req.URL.Path, "/api/v1/transactions/")
+		response := TransactionResponse{Data: TransactionResource{Resource: Resource{Type: "transactions", ID: id}}}
+		body, err := json.Marshal(response)
+		require.NoError(t, err)
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(body)
+	}))
+}
+
+func TestClientConcurrentUse(t *testing.T) {
+	var calls int32
+	server := newTransactionServer(t, &calls)
+	defer server.Close()
+
+	client := NewClient("token", WithRateLimit(1000, 5))
+	client.baseURL = server.URL
+
+	var wg sync.WaitGroup
+	errs := make([]error, 20)
+	ids := make([]string, 20)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			response, err := client.Transaction(fmt.Sprint(i))
+			errs[i] = err
+			ids[i] = response.Data.ID
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range errs {
+		require.NoError(t, errs[i])
+		require.Equal(t, fmt.Sprint(i), ids[i])
+	}
+	require.Equal(t, int32(20), calls)
+}
+
+func TestRateLimitFailFast(t *testing.T) {
+	var calls int32
+	server := newTransactionServer(t, &calls)
+	defer server.Close()
+
+	// The bucket only refills every ~17 minutes so only the burst gets
+	// through.
+	client := NewClient("token", WithRateLimit(0.001, 2))
+	client.baseURL = server.URL
+	failFast := client.WithContext(FailFast(context.Background()))
+
+	for i := 0; i < 2; i++ {
+		_, err := failFast.Transaction("id")
+		require.NoError(t, err)
+	}
+	_, err := failFast.Transaction("id")
+	require.True(t, errors.Is(err, ErrRateLimited), err)
+	require.Equal(t, int32(2), calls)
+
+	// Waiting would go past the deadline so it fails straight away too.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err = client.WithContext(ctx).Transaction("id")
+	require.True(t, errors.Is(err, ErrRateLimited), err)
+	require.Equal(t, int32(2), calls)
+}
+
+func TestRateLimitFailFastWithRetries(t *testing.T) {
+	var calls int32
+	server := newTransactionServer(t, &calls)
+	defer server.Close()
+
+	// If going over the limit were retried, the hour long backoff would time
+	// the test out.
+	client := NewClient("token", WithRateLimit(0.001, 1), WithRetries(3, time.Hour))
+	client.baseURL = server.URL
+	failFast := client.WithContext(FailFast(context.Background()))
+
+	_, err := failFast.Transactions.Get("id")
+	require.NoError(t, err)
+	_, err = failFast.Transactions.Get("id")
+	require.True(t, errors.Is(err, ErrRateLimited), err)
+	require.Equal(t, int32(1), calls)
+}
+
+func TestWithContextCancelled(t *testing.T) {
+	var calls int32
+	server := newTransactionServer(t, &calls)
+	defer server.Close()
+
+	client := NewClient("token")
+	client.baseURL = server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.WithContext(ctx).Transaction("id")
+	require.True(t, errors.Is(err, context.Canceled), err)
+	require.Equal(t, int32(0), calls)
+
+	// The original client isn't affected.
+	_, err = client.Transaction("id")
+	require.NoError(t, err)
+}
+
+// stubTags is a TagsAPI that doesn't send any requests.
+type stubTags struct{}
+
+func (stubTags) List(options ...TagsOption) (TagsResponse, error) {
+	return TagsResponse{Data: []TagResource{{ID: "stub"}}}, nil
+}
+
+func (stubTags) Next(tags TagsResponse) (TagsResponse, error) {
+	return TagsResponse{}, ErrNoNextPage
+}
+
+func TestWithContextKeepsReplacedServices(t *testing.T) {
+	client := NewClient("token")
+	client.Tags = NewTagsClient(stubTags{})
+
+	copied := client.WithContext(context.Background())
+	tags, err := copied.Tags.api()
+	require.NoError(t, err)
+	require.Equal(t, stubTags{}, tags)
+	response, err := copied.Tags()
+	require.NoError(t, err)
+	require.Equal(t, "stub", response.Data[0].ID)
+	// The services that weren't replaced use the copy.
+	transactions, err := copied.Transactions.api()
+	require.NoError(t, err)
+	require.True(t, transactions.(*TransactionsService).client == copied)
+	transactions, err = client.Transactions.api()
+	require.NoError(t, err)
+	require.True(t, transactions.(*TransactionsService).client == client)
+}
+
+func TestSubClientWithoutImplementation(t *testing.T) {
+	var accounts AccountsClient
+	_, err := accounts.List()
+	require.Equal(t, ErrNoImplementation, err)
+	_, err = accounts.Get("id")
+	require.Equal(t, ErrNoImplementation, err)
+
+	// A function that wasn't created with NewTransactionsClient can still be
+	// called but has nothing to send the other requests with.
+	transactions := TransactionsClient(func(options ...TransactionsOption) (TransactionsResponse, error) {
+		return TransactionsResponse{}, nil
+	})
+	_, err = transactions.List()
+	require.NoError(t, err)
+	_, err = transactions.Get("id")
+	require.Equal(t, ErrNoImplementation, err)
+	require.Equal(t, ErrNoImplementation, transactions.Tag("id", "tag"))
+
+	// The client's own are left alone when it's copied.
+	client := NewClient("token")
+	client.Transactions = transactions
+	copied := client.WithContext(context.Background())
+	require.Equal(t, ErrNoImplementation, copied.Transactions.Tag("id", "tag"))
+}
+
+func TestTransactionsByID(t *testing.T) {
+	var calls, inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		id := strings.TrimPrefix(req.URL.Path, "/api/v1/transactions/")
+		if id == "missing" {
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"errors":[{"status":"404","title":"Not Found","detail":"no such transaction"}]}`))
+			return
+		}
+		body, err := json.Marshal(TransactionResponse{Data: TransactionResource{Resource: Resource{Type: "transactions", ID: id}}})
+		require.NoError(t, err)
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.baseURL = server.URL
+
+	ids := []string{"a", "b", "missing", "c", "a", "d", "e", "f"}
+	results := client.TransactionsByID(context.Background(), ids, 3)
+	require.Len(t, results, len(ids))
+	for i, id := range ids {
+		require.Equal(t, id, results[i].ID)
+		if id == "missing" {
+			require.EqualError(t, results[i].Err, "1 error occurred:\n\t* no such transaction\n\n")
+			continue
+		}
+		require.NoError(t, results[i].Err)
+		require.Equal(t, id, results[i].Transaction.ID)
+	}
+
+	// "a" is only fetched once and there are never more than 3 requests at
+	// once.
+	require.Equal(t, int32(7), calls)
+	require.True(t, maxInFlight <= 3, maxInFlight)
+}
+
+func TestTransactionsByIDCancelled(t *testing.T) {
+	var calls int32
+	server := newTransactionServer(t, &calls)
+	defer server.Close()
+
+	client := NewClient("token")
+	client.baseURL = server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := client.TransactionsByID(ctx, []string{"a", "b"}, 2)
+	for _, result := range results {
+		require.True(t, errors.Is(result.Err, context.Canceled), result.Err)
+	}
+	require.Equal(t, int32(0), calls)
+}
+
+func TestFollow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		var response interface{}
+		switch req.URL.Path {
+		case "/api/v1/accounts/account-id":
+			response = AccountResponse{Data: AccountResource{ID: "account-id", Type: "accounts"}}
+		case "/api/v1/accounts/account-id/transactions":
+			response = TransactionsResponse{Data: []TransactionResource{{Resource: Resource{ID: "transaction-id", Type: "transactions"}}}}
+		case "/api/v1/transactions/transaction-id":
+			response = TransactionResponse{Data: TransactionResource{Resource: Resource{ID: "transaction-id", Type: "transactions"}}}
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"errors":[{"status":"404","title":"Not Found","detail":"not found"}]}`))
+			return
+		}
+		body, err := json.Marshal(response)
+		require.NoError(t, err)
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL))
+
+	var account AccountResource
+	account.Relationships.Transactions.Links.Related = server.URL + "/api/v1/accounts/account-id/transactions"
+	transactions, err := account.FetchTransactions(client)
+	require.NoError(t, err)
+	require.Equal(t, "transaction-id", transactions.Data[0].ID)
+
+	var transaction TransactionResource
+	transaction.Relationships.Account.Links.Related = server.URL + "/api/v1/accounts/account-id"
+	accountResponse, err := transaction.FetchAccount(client)
+	require.NoError(t, err)
+	require.Equal(t, "account-id", accountResponse.Data.ID)
+
+	// Uncategorised transactions don't have a category to fetch.
+	_, err = transaction.FetchCategory(client)
+	require.True(t, errors.Is(err, ErrNoLink), err)
+
+	var event WebhookEventResource
+	event.Relationships.Transaction.Links.Related = server.URL + "/api/v1/transactions/transaction-id"
+	transactionResponse, err := event.FetchTransaction(client)
+	require.NoError(t, err)
+	require.Equal(t, "transaction-id", transactionResponse.Data.ID)
+
+	var out TransactionResponse
+	require.EqualError(t, client.Follow(server.URL+"/api/v1/transactions/other", &out), "1 error occurred:\n\t* not found\n\n")
+
+	// The token is never sent anywhere else.
+	err = client.Follow("https://example.com/api/v1/transactions/transaction-id", &out)
+	require.True(t, errors.Is(err, ErrForeignLink), err)
+}
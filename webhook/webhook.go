@@ -0,0 +1,139 @@
+// Package webhook receives events sent by UpBank to registered webhooks.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/nick96/upngo"
+)
+
+// SignatureHeader is the header UpBank puts the request body's signature in.
+const SignatureHeader = "X-Up-Authenticity-Signature"
+
+// Event is the body of a request UpBank sends to a webhook.
+type Event struct {
+	Data upngo.WebhookEventResource `json:"data"`
+}
+
+// Handler handles a webhook event.
+type Handler interface {
+	HandleEvent(event Event) error
+}
+
+// HandlerFunc lets an ordinary function be used as a Handler.
+type HandlerFunc func(event Event) error
+
+// HandleEvent calls f(event).
+func (f HandlerFunc) HandleEvent(event Event) error {
+	return f(event)
+}
+
+// VerifySignature reports whether `signature`, the hex encoded value of the
+// SignatureHeader, is the HMAC-SHA256 of `body` signed with `secretKey`.
+func VerifySignature(secretKey string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write(body)
+	return hmac.Equal(expected, mac.Sum(nil))
+}
+
+// SecretStore looks up the secret key of a webhook by its ID.
+type SecretStore interface {
+	WebhookSecret(webhookID string) (string, error)
+}
+
+// staticSecret is a SecretStore that has the same secret key for every
+// webhook.
+type staticSecret string
+
+func (s staticSecret) WebhookSecret(webhookID string) (string, error) {
+	return string(s), nil
+}
+
+// Receiver is an http.Handler that verifies requests really came from UpBank
+// and dispatches the events in them to the handlers registered for each event
+// type.
+type Receiver struct {
+	secrets  SecretStore
+	handlers map[upngo.WebhookEventType][]Handler
+}
+
+// NewReceiver creates a receiver for a webhook with the given secret key,
+// which is returned by the API when the webhook is registered.
+func NewReceiver(secretKey string) *Receiver {
+	return NewReceiverWithSecrets(staticSecret(secretKey))
+}
+
+// NewReceiverWithSecrets creates a receiver that looks up the secret key of
+// the webhook each event was sent to in `secrets`. This lets one receiver
+// serve several webhooks.
+func NewReceiverWithSecrets(secrets SecretStore) *Receiver {
+	return &Receiver{
+		secrets:  secrets,
+		handlers: make(map[upngo.WebhookEventType][]Handler),
+	}
+}
+
+// Handle registers a handler for the event type. Handlers are called in the
+// order they're registered.
+func (r *Receiver) Handle(eventType upngo.WebhookEventType, handler Handler) {
+	r.handlers[eventType] = append(r.handlers[eventType], handler)
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "POST only please", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	// We don't use the strict unmarshal here because UpBank adding a field
+	// to events shouldn't stop us from handling them. The event is parsed
+	// before it's verified because we need the webhook ID to find the
+	// secret key, but nothing in it is trusted until it has been verified.
+	var event Event
+	parseErr := json.Unmarshal(body, &event)
+
+	webhookID := event.Data.Relationships.Webhook.Data.ID
+	secretKey, err := r.secrets.WebhookSecret(webhookID)
+	if err != nil {
+		log.Printf("Failed to find secret key for webhook %s: %v", webhookID, err)
+		http.Error(w, "unknown webhook", http.StatusUnauthorized)
+		return
+	}
+
+	if !VerifySignature(secretKey, body, req.Header.Get(SignatureHeader)) {
+		log.Printf("Authenticity check of webhook request failed")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	if parseErr != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	for _, handler := range r.handlers[event.Data.Attributes.EventType] {
+		if err := handler.HandleEvent(event); err != nil {
+			log.Printf("Failed to handle %s event %s: %v", event.Data.Attributes.EventType, event.Data.ID, err)
+			http.Error(w, "failed to handle event", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
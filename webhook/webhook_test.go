@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nick96/upngo"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(secretKey string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newRequest(t *testing.T, eventType upngo.WebhookEventType, secretKey string) *http.Request {
+	return newWebhookRequest(t, "webhook-id", eventType, secretKey)
+}
+
+func newWebhookRequest(t *testing.T, webhookID string, eventType upngo.WebhookEventType, secretKey string) *http.Request {
+	var event Event
+	event.Data.ID = "event-id"
+	event.Data.Relationships.Webhook.Data.ID = webhookID
+	event.Data.Attributes.EventType = eventType
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set(SignatureHeader, sign(secretKey, body))
+	return req
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"data":{}}`)
+	require.True(t, VerifySignature("secret", body, sign("secret", body)))
+	require.False(t, VerifySignature("other", body, sign("secret", body)))
+	require.False(t, VerifySignature("secret", body, "not hex"))
+}
+
+func TestReceiverDispatches(t *testing.T) {
+	receiver := NewReceiver("secret")
+	var handled []string
+	receiver.Handle(upngo.WebhookEventTypePing, HandlerFunc(func(event Event) error {
+		handled = append(handled, event.Data.ID)
+		return nil
+	}))
+
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, newRequest(t, upngo.WebhookEventTypePing, "secret"))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, []string{"event-id"}, handled)
+
+	// Events without handlers are fine, they're just ignored.
+	recorder = httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, newRequest(t, upngo.WebhookEventTypeTransactionDeleted, "secret"))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, []string{"event-id"}, handled)
+}
+
+func TestReceiverRejects(t *testing.T) {
+	receiver := NewReceiver("secret")
+	receiver.Handle(upngo.WebhookEventTypePing, HandlerFunc(func(event Event) error {
+		return errors.New("boom")
+	}))
+
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, newRequest(t, upngo.WebhookEventTypePing, "wrong"))
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, newRequest(t, upngo.WebhookEventTypePing, "secret"))
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+type mapSecrets map[string]string
+
+func (m mapSecrets) WebhookSecret(webhookID string) (string, error) {
+	secret, ok := m[webhookID]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return secret, nil
+}
+
+func TestReceiverWithSecrets(t *testing.T) {
+	receiver := NewReceiverWithSecrets(mapSecrets{"first": "secret-1", "second": "secret-2"})
+	var handled int
+	receiver.Handle(upngo.WebhookEventTypePing, HandlerFunc(func(event Event) error {
+		handled++
+		return nil
+	}))
+
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, newWebhookRequest(t, "first", upngo.WebhookEventTypePing, "secret-1"))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, newWebhookRequest(t, "second", upngo.WebhookEventTypePing, "secret-2"))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, 2, handled)
+
+	// Each webhook's events have to be signed with its own secret.
+	recorder = httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, newWebhookRequest(t, "second", upngo.WebhookEventTypePing, "secret-1"))
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, newWebhookRequest(t, "unknown", upngo.WebhookEventTypePing, "secret-1"))
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Equal(t, 2, handled)
+}
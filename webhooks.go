@@ -1,6 +1,10 @@
 package upngo
 
-import "time"
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
 
 type WebhookEventType string
 
@@ -97,3 +101,292 @@ type WebhookEventResource struct {
 type WebhookPingResponse struct {
 	Data WebhookEventResource `json:"data"`
 }
+
+// WebhookDeliveryStatus is whether an event was delivered to a webhook.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusDelivered       WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryStatusUndeliverable   WebhookDeliveryStatus = "UNDELIVERABLE"
+	WebhookDeliveryStatusBadResponseCode WebhookDeliveryStatus = "BAD_RESPONSE_CODE"
+)
+
+type WebhookDeliveryLogRequest struct {
+	Body string `json:"body"`
+}
+
+type WebhookDeliveryLogResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"`
+}
+
+type WebhookDeliveryLogAttributes struct {
+	Request WebhookDeliveryLogRequest `json:"request"`
+	// Response is nil if the webhook didn't respond at all.
+	Response       *WebhookDeliveryLogResponse `json:"response"`
+	DeliveryStatus WebhookDeliveryStatus       `json:"deliveryStatus"`
+	CreatedAt      time.Time                   `json:"createdAt"`
+}
+
+type WebhookDeliveryLogRelationships struct {
+	WebhookEvent struct {
+		Data DataObject `json:"data"`
+	} `json:"webhookEvent"`
+}
+
+// WebhookDeliveryLogResource is a record of an attempt to deliver an event to
+// a webhook.
+type WebhookDeliveryLogResource struct {
+	Type          string                          `json:"type"`
+	ID            string                          `json:"id"`
+	Attributes    WebhookDeliveryLogAttributes    `json:"attributes"`
+	Relationships WebhookDeliveryLogRelationships `json:"relationships"`
+}
+
+// WebhookLogsResponse represents a response from the webhook logs endpoint.
+type WebhookLogsResponse struct {
+	Data  []WebhookDeliveryLogResource `json:"data"`
+	Links LinksObject                  `json:"links"`
+}
+
+// WebhooksClient is a Client's webhooks endpoints, e.g.
+// `client.Webhooks.Create(url)`. Calling it lists webhooks, like List, so code
+// from before the endpoints were grouped, `client.Webhooks()`, still works.
+//
+// Create one with NewWebhooksClient, otherwise its methods return
+// ErrNoImplementation.
+type WebhooksClient func(options ...WebhooksOption) (WebhooksResponse, error)
+
+// NewWebhooksClient creates a WebhooksClient that uses `api`, e.g. to replace
+// a Client's Webhooks with a mock.
+func NewWebhooksClient(api WebhooksAPI) WebhooksClient {
+	return func(options ...WebhooksOption) (WebhooksResponse, error) {
+		if len(options) == 1 && provide(options[0], api) {
+			return WebhooksResponse{}, nil
+		}
+		return api.List(options...)
+	}
+}
+
+// api is the implementation the sub-client wraps. Only sub-clients created
+// with NewWebhooksClient have one.
+func (c WebhooksClient) api() (WebhooksAPI, error) {
+	if c == nil {
+		return nil, ErrNoImplementation
+	}
+	api, ok := unwrap(func(request implementation) { c(request) }).(WebhooksAPI)
+	if !ok {
+		return nil, ErrNoImplementation
+	}
+	return api, nil
+}
+
+// List lists all the webhooks.
+func (c WebhooksClient) List(options ...WebhooksOption) (WebhooksResponse, error) {
+	if c == nil {
+		return WebhooksResponse{}, ErrNoImplementation
+	}
+	return c(options...)
+}
+
+// Next retrieves the page of webhooks after the given response. If there are
+// no more pages then ErrNoNextPage is returned.
+func (c WebhooksClient) Next(webhooks WebhooksResponse) (WebhooksResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return WebhooksResponse{}, err
+	}
+	return api.Next(webhooks)
+}
+
+// Get retrieves a webhook by its ID.
+func (c WebhooksClient) Get(id string) (WebhookResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return WebhookResponse{}, err
+	}
+	return api.Get(id)
+}
+
+// Create registers a webhook for the given URL.
+func (c WebhooksClient) Create(webhookURL string, options ...RegisterWebhookOption) (WebhookResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return WebhookResponse{}, err
+	}
+	return api.Create(webhookURL, options...)
+}
+
+// Delete deletes the webhook with the given ID.
+func (c WebhooksClient) Delete(id string) error {
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+	return api.Delete(id)
+}
+
+// Ping sends a ping event to the webhook with the given ID.
+func (c WebhooksClient) Ping(id string) (WebhookPingResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return WebhookPingResponse{}, err
+	}
+	return api.Ping(id)
+}
+
+// Logs lists the attempts to deliver events to the webhook with the given ID.
+func (c WebhooksClient) Logs(id string, options ...WebhookLogsOption) (WebhookLogsResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return WebhookLogsResponse{}, err
+	}
+	return api.Logs(id, options...)
+}
+
+// NextLogs retrieves the page of delivery logs after the given response. If
+// there are no more pages then ErrNoNextPage is returned.
+func (c WebhooksClient) NextLogs(logs WebhookLogsResponse) (WebhookLogsResponse, error) {
+	api, err := c.api()
+	if err != nil {
+		return WebhookLogsResponse{}, err
+	}
+	return api.NextLogs(logs)
+}
+
+// WebhooksService talks to the webhooks endpoints. It's what a Client's
+// Webhooks uses unless it's been replaced.
+type WebhooksService struct {
+	client *Client
+}
+
+// List lists all the webhooks.
+func (s *WebhooksService) List(options ...WebhooksOption) (WebhooksResponse, error) {
+	return s.list(s.client.buildURL("webhooks"), options...)
+}
+
+// Next retrieves the page of webhooks after the given response. If there are
+// no more pages then ErrNoNextPage is returned.
+func (s *WebhooksService) Next(webhooks WebhooksResponse) (WebhooksResponse, error) {
+	if webhooks.Links.Next == "" {
+		return WebhooksResponse{}, ErrNoNextPage
+	}
+	return s.list(webhooks.Links.Next)
+}
+
+func (s *WebhooksService) list(url string, options ...WebhooksOption) (WebhooksResponse, error) {
+	query := make([]Option, len(options))
+	for i, option := range options {
+		query[i] = option
+	}
+
+	var webhooksResponse WebhooksResponse
+	err := s.client.do(request{
+		name:    "get webhooks",
+		method:  http.MethodGet,
+		url:     url,
+		options: query,
+	}, &webhooksResponse)
+	return webhooksResponse, err
+}
+
+// Get retrieves a webhook by its ID.
+func (s *WebhooksService) Get(id string) (WebhookResponse, error) {
+	var webhookResponse WebhookResponse
+	err := s.client.do(request{
+		name:   "get webhook by ID",
+		method: http.MethodGet,
+		url:    s.client.buildURL("webhooks", id),
+	}, &webhookResponse)
+	return webhookResponse, err
+}
+
+// Create registers a webhook for the given URL. The response includes the
+// webhook's secret key, which is the only time the API gives it out.
+func (s *WebhooksService) Create(webhookURL string, options ...RegisterWebhookOption) (WebhookResponse, error) {
+	var description string
+	for _, option := range options {
+		if option.Name() == "description" {
+			description = option.Value()
+		}
+	}
+
+	if len(webhookURL) > MaxWebhookURLLength {
+		return WebhookResponse{}, fmt.Errorf("webhook URL too long. Max length is %d", MaxWebhookURLLength)
+	}
+
+	if len(description) > MaxWebhookDescriptionLength {
+		return WebhookResponse{}, fmt.Errorf("webhook description too long. Max length is %d", MaxWebhookDescriptionLength)
+	}
+
+	input := RegisterWebhookRequest{
+		Data: WebhookInputResource{
+			Attributes: WebhookInputResourceAttributes{
+				URL:         webhookURL,
+				Description: description,
+			},
+		},
+	}
+	var webhookResponse WebhookResponse
+	err := s.client.do(request{
+		name:   "register webhook",
+		method: http.MethodPost,
+		url:    s.client.buildURL("webhooks"),
+		body:   input,
+		status: http.StatusCreated,
+	}, &webhookResponse)
+	return webhookResponse, err
+}
+
+// Delete deletes the webhook with the given ID so it stops receiving events.
+func (s *WebhooksService) Delete(id string) error {
+	return s.client.do(request{
+		name:   "delete webhook",
+		method: http.MethodDelete,
+		url:    s.client.buildURL("webhooks", id),
+		status: http.StatusNoContent,
+	}, nil)
+}
+
+// Ping sends a ping event to the webhook with the given ID.
+func (s *WebhooksService) Ping(id string) (WebhookPingResponse, error) {
+	var webhookPingResponse WebhookPingResponse
+	err := s.client.do(request{
+		name:   "ping webhook",
+		method: http.MethodPost,
+		url:    s.client.buildURL("webhooks", id, "ping"),
+		status: http.StatusCreated,
+	}, &webhookPingResponse)
+	return webhookPingResponse, err
+}
+
+// Logs lists the attempts to deliver events to the webhook with the given ID,
+// newest first.
+func (s *WebhooksService) Logs(id string, options ...WebhookLogsOption) (WebhookLogsResponse, error) {
+	return s.logs(s.client.buildURL("webhooks", id, "logs"), options...)
+}
+
+// NextLogs retrieves the page of delivery logs after the given response. If
+// there are no more pages then ErrNoNextPage is returned.
+func (s *WebhooksService) NextLogs(logs WebhookLogsResponse) (WebhookLogsResponse, error) {
+	if logs.Links.Next == "" {
+		return WebhookLogsResponse{}, ErrNoNextPage
+	}
+	return s.logs(logs.Links.Next)
+}
+
+func (s *WebhooksService) logs(url string, options ...WebhookLogsOption) (WebhookLogsResponse, error) {
+	query := make([]Option, len(options))
+	for i, option := range options {
+		query[i] = option
+	}
+
+	var logsResponse WebhookLogsResponse
+	err := s.client.do(request{
+		name:    "get webhook logs",
+		method:  http.MethodGet,
+		url:     url,
+		options: query,
+	}, &logsResponse)
+	return logsResponse, err
+}